@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// defaultAverageMonths is the number of months averaged by
+// the average strategy of budget autoassign, unless told otherwise.
+const defaultAverageMonths = 3
+
+// assignStrategies plan the money to assign to each category from the
+// reports of the months before, oldest first. Strategies which plan
+// from something other than reports, as templates do, are not kept here.
+var assignStrategies = map[string]func(earlier [][]*pgo.CategoryReport, ISOCode string) (map[string]cc.Money, error){
+	// copy assigns what was assigned the month before
+	"copy": func(earlier [][]*pgo.CategoryReport, ISOCode string) (map[string]cc.Money, error) {
+		plan := map[string]cc.Money{}
+		for _, report := range earlier[len(earlier)-1] {
+			plan[report.Name] = cc.NewMoney(max(report.Assigned, 0), ISOCode)
+		}
+		return plan, nil
+	},
+	// activity assigns what was spent the month before
+	"activity": func(earlier [][]*pgo.CategoryReport, ISOCode string) (map[string]cc.Money, error) {
+		return averageSpending(earlier[len(earlier)-1:], ISOCode)
+	},
+	// average assigns what was spent in an average month
+	"average": averageSpending,
+}
+
+// averageSpending plans to assign each category what was spent from it in
+// an average month, rounded to the nearest minor unit. Months in which
+// more came into a category than went out count as nothing spent.
+func averageSpending(earlier [][]*pgo.CategoryReport, ISOCode string) (map[string]cc.Money, error) {
+	totals := map[string]cc.Money{}
+	for _, monthReports := range earlier {
+		for _, report := range monthReports {
+			total, ok := totals[report.Name]
+			if !ok {
+				total = cc.NewMoney(0, ISOCode)
+			}
+			var err error
+			if total, err = total.Add(cc.NewMoney(max(-report.Activity, 0), ISOCode)); err != nil {
+				return nil, fmt.Errorf("could not total spending of category %s: %w", report.Name, err)
+			}
+			totals[report.Name] = total
+		}
+	}
+	plan := map[string]cc.Money{}
+	months := int64(len(earlier))
+	for category, total := range totals {
+		plan[category] = cc.NewMoney((total.Amount+months/2)/months, ISOCode)
+	}
+	return plan, nil
+}
+
+// assignChange is a planned change to the money assigned to a category.
+type assignChange struct {
+	category string
+	current  cc.Money
+	planned  cc.Money
+}
+
+// delta returns the money to assign to the category to make the change.
+func (a assignChange) delta() (cc.Money, error) {
+	return a.planned.Sub(a.current)
+}
+
+// planChanges compares a plan with the money already assigned to each
+// category in the month, returning the changes needed to carry it out in
+// order of category name. Categories left out of the plan are left alone,
+// as are any planned which are not among the given categories.
+func planChanges(plan map[string]cc.Money, current []*pgo.CategoryReport, categories []string, ISOCode string) ([]assignChange, error) {
+	assigned := map[string]int64{}
+	for _, report := range current {
+		assigned[report.Name] = report.Assigned
+	}
+	changes := []assignChange{}
+	for category, planned := range plan {
+		if !slices.Contains(categories, category) {
+			continue
+		}
+		change := assignChange{category: category, current: cc.NewMoney(assigned[category], ISOCode), planned: planned}
+		delta, err := change.delta()
+		if err != nil {
+			return nil, fmt.Errorf("could not plan category %s: %w", category, err)
+		}
+		if !delta.IsZero() {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortFunc(changes, func(a, b assignChange) int { return cmp.Compare(a.category, b.category) })
+	return changes, nil
+}
+
+// previewChanges lays out planned changes as rows of a
+// table, along with the total change in money assigned.
+func previewChanges(changes []assignChange, ISOCode string) ([][]string, cc.Money, error) {
+	rows := [][]string{}
+	total := cc.NewMoney(0, ISOCode)
+	for _, change := range changes {
+		delta, err := change.delta()
+		if err != nil {
+			return nil, cc.Money{}, err
+		}
+		if total, err = total.Add(delta); err != nil {
+			return nil, cc.Money{}, err
+		}
+		rows = append(rows, []string{change.category, change.current.String(), change.planned.String(), signed(delta)})
+	}
+	return rows, total, nil
+}
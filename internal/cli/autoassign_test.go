@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestAssignStrategies(t *testing.T) {
+	earlier := [][]*pgo.CategoryReport{
+		{
+			{Name: "Groceries", Assigned: 40000, Activity: -35000},
+			{Name: "Dining", Assigned: 10000, Activity: -12000},
+		},
+		{
+			{Name: "Groceries", Assigned: 45000, Activity: -40001},
+			{Name: "Dining", Assigned: 10000, Activity: 2000},
+			{Name: "Gifts", Assigned: 5000, Activity: 0},
+		},
+	}
+
+	tests := []struct {
+		strategy string
+		expected map[string]int64
+	}{
+		{strategy: "copy", expected: map[string]int64{"Groceries": 45000, "Dining": 10000, "Gifts": 5000}},
+		{strategy: "activity", expected: map[string]int64{"Groceries": 40001, "Dining": 0, "Gifts": 0}},
+		{strategy: "average", expected: map[string]int64{"Groceries": 37501, "Dining": 6000, "Gifts": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			plan, err := assignStrategies[tt.strategy](earlier, "USD")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := map[string]int64{}
+			for category, amount := range plan {
+				actual[category] = amount.Amount
+			}
+			if !maps.Equal(actual, tt.expected) {
+				t.Errorf("expected plan %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPlanChanges(t *testing.T) {
+	plan := map[string]cc.Money{
+		"Groceries": cc.NewMoney(45000, "USD"),
+		"Dining":    cc.NewMoney(10000, "USD"),
+		"Rent":      cc.NewMoney(120000, "USD"),
+		"Deleted":   cc.NewMoney(100, "USD"),
+	}
+	current := []*pgo.CategoryReport{
+		{Name: "Groceries", Assigned: 20000},
+		{Name: "Dining", Assigned: 10000},
+		{Name: "Gifts", Assigned: 5000},
+	}
+	categories := []string{"Dining", "Gifts", "Groceries", "Rent"}
+
+	changes, err := planChanges(plan, current, categories, "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, change := range changes {
+		names = append(names, change.category)
+	}
+	if !slices.Equal(names, []string{"Groceries", "Rent"}) {
+		t.Fatalf("expected changes to Groceries and Rent, got %q", names)
+	}
+
+	rows, total, err := previewChanges(changes, "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"Groceries", "$200.00", "$450.00", "+$250.00"},
+		{"Rent", "$0.00", "$1,200.00", "+$1,200.00"},
+	}
+	if !slices.EqualFunc(rows, expected, slices.Equal) {
+		t.Errorf("expected rows %q, got %q", expected, rows)
+	}
+	if !total.Equal(cc.NewMoney(145000, "USD")) {
+		t.Errorf("expected a total change of $1,450.00, got %v", total)
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// eighthBlocks are the blocks filling a cell from the left by
+// eighths, as used to draw horizontal bars of fine length.
+var eighthBlocks = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+// riseBlocks are the blocks filling a cell from the bottom by
+// eighths, as used to draw sparklines.
+var riseBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// scaleEighths scales a value against the largest value
+// shown to a number of eighths of the given cell count.
+func scaleEighths(value, largest int64, cells int) int {
+	if value <= 0 || largest <= 0 {
+		return 0
+	}
+	// in floating point, as amounts times eighths may overflow
+	return int(float64(value) / float64(largest) * float64(cells*8))
+}
+
+// bar returns a horizontal bar as long as the given number of eighths of a cell.
+func bar(eighths int) string {
+	return strings.Repeat(string(eighthBlocks[8]), eighths/8) + strings.TrimRight(string(eighthBlocks[eighths%8]), " ")
+}
+
+// barChart draws a horizontal bar for each value, scaled such that the
+// largest spans the given width. Bars are labelled on the left, and
+// followed by the given descriptions of their values.
+func barChart(labels []string, values []int64, descriptions []string, width int, paintBar func(string) string) string {
+	largest := int64(0)
+	for _, value := range values {
+		largest = max(largest, value)
+	}
+	labelWidth := MaxOfStrings(labels...)
+	var out strings.Builder
+	for i, label := range labels {
+		drawn := bar(scaleEighths(values[i], largest, width))
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(drawn))
+		out.WriteString("  " + label + strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label)))
+		out.WriteString(" │" + paintBar(drawn) + padding + " " + descriptions[i] + "\n")
+	}
+	return out.String()
+}
+
+// sparkline draws each value in a single cell, with the height of each
+// scaled against the largest value. Values below zero are drawn as
+// zero, such that a line of spending ignores refunds.
+func sparkline(values []int64) string {
+	largest := int64(0)
+	for _, value := range values {
+		largest = max(largest, value)
+	}
+	var out strings.Builder
+	for _, value := range values {
+		// the lowest block is kept for zero, so that every cell
+		// is visible and no spending reads apart from a little
+		level := 1 + scaleEighths(value, largest, 1)*7/8
+		out.WriteRune(riseBlocks[min(level, 8)])
+	}
+	return out.String()
+}
+
+// lineChart draws the values as a line of the given height, rising from
+// the smallest value to the largest such that changes show even when all
+// values are far from zero. Each value takes a cell of the given width,
+// with the line turning up or down at the start of the cell to meet it.
+// The axis is labelled with the given descriptions of the smallest and
+// largest values, and each cell with the label beneath it.
+func lineChart(values []int64, labels []string, lowest, highest string, height, cellWidth int, paintLine func(string) string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low, high = min(low, value), max(high, value)
+	}
+	levels := make([]int, len(values))
+	if high > low {
+		for i, value := range values {
+			levels[i] = int(math.Round(float64(value-low) / float64(high-low) * float64(height-1)))
+		}
+	}
+
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", len(values)*cellWidth))
+	}
+	for i, level := range levels {
+		start := i * cellWidth
+		previous := level
+		if i > 0 {
+			previous = levels[i-1]
+		}
+		switch {
+		case level > previous:
+			grid[previous][start] = '╯'
+			for row := previous + 1; row < level; row++ {
+				grid[row][start] = '│'
+			}
+			grid[level][start] = '╭'
+		case level < previous:
+			grid[previous][start] = '╮'
+			for row := level + 1; row < previous; row++ {
+				grid[row][start] = '│'
+			}
+			grid[level][start] = '╰'
+		default:
+			grid[level][start] = '─'
+		}
+		for col := start + 1; col < start+cellWidth; col++ {
+			grid[level][col] = '─'
+		}
+	}
+
+	axisWidth := MaxOfStrings(lowest, highest)
+	var out strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		axis := ""
+		switch row {
+		case height - 1:
+			axis = highest
+		case 0:
+			axis = lowest
+		}
+		out.WriteString("  " + strings.Repeat(" ", axisWidth-utf8.RuneCountInString(axis)) + axis + " │")
+		out.WriteString(paintLine(strings.TrimRight(string(grid[row]), " ")) + "\n")
+	}
+	out.WriteString("  " + strings.Repeat(" ", axisWidth) + " └" + strings.Repeat("─", len(values)*cellWidth) + "\n")
+	out.WriteString("  " + strings.Repeat(" ", axisWidth) + "  ")
+	for _, label := range labels {
+		label = firstNChars(label, cellWidth-1)
+		out.WriteString(label + strings.Repeat(" ", cellWidth-utf8.RuneCountInString(label)))
+	}
+	out.WriteString("\n")
+	return out.String()
+}
+
+// progressBar draws a bar of the given width filled by the given
+// fraction, with the rest of it shaded such that the whole width shows.
+// Fractions beyond one fill the bar.
+func progressBar(fraction float64, width int) string {
+	eighths := min(int(max(fraction, 0)*float64(width*8)), width*8)
+	drawn := bar(eighths)
+	return drawn + strings.Repeat("░", width-utf8.RuneCountInString(drawn))
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func plain(text string) string { return text }
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		eighths  int
+		expected string
+	}{
+		{eighths: 0, expected: ""},
+		{eighths: 3, expected: "▍"},
+		{eighths: 8, expected: "█"},
+		{eighths: 20, expected: "██▌"},
+	}
+
+	for _, tt := range tests {
+		if actual := bar(tt.eighths); actual != tt.expected {
+			t.Errorf("bar(%d): expected %q, got %q", tt.eighths, tt.expected, actual)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		expected string
+	}{
+		{fraction: 0, expected: "░░░░"},
+		{fraction: 0.5, expected: "██░░"},
+		{fraction: 0.3, expected: "█▏░░"},
+		{fraction: 1.5, expected: "████"},
+		{fraction: -1, expected: "░░░░"},
+	}
+
+	for _, tt := range tests {
+		if actual := progressBar(tt.fraction, 4); actual != tt.expected {
+			t.Errorf("progressBar(%v): expected %q, got %q", tt.fraction, tt.expected, actual)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	actual := barChart(
+		[]string{"Rent", "Dining"},
+		[]int64{120000, 30000},
+		[]string{"$1,200.00", "$300.00"},
+		4, plain,
+	)
+	expected := "" +
+		"  Rent   │████ $1,200.00\n" +
+		"  Dining │█    $300.00\n"
+	if actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int64
+		expected string
+	}{
+		{name: "rising", values: []int64{0, 100, 200, 400, 800}, expected: "▁▁▂▄█"},
+		{name: "flat", values: []int64{500, 500, 500}, expected: "███"},
+		{name: "refunds drawn as zero", values: []int64{-300, 800}, expected: "▁█"},
+		{name: "nothing spent", values: []int64{0, 0}, expected: "▁▁"},
+		{name: "empty", values: []int64{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := sparkline(tt.values); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLineChart(t *testing.T) {
+	actual := lineChart([]int64{100, 300, 200}, []string{"Aug", "Sep", "Oct"}, "$1", "$3", 3, 4, plain)
+	expected := "" +
+		"  $3 │    ╭───╮\n" +
+		"     │    │   ╰───\n" +
+		"  $1 │────╯\n" +
+		"     └────────────\n" +
+		"      Aug Sep Oct \n"
+	if actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	falling := lineChart([]int64{300, 100}, []string{"Sep", "Oct"}, "$1", "$3", 2, 3, plain)
+	expected = "" +
+		"  $3 │───╮\n" +
+		"  $1 │   ╰──\n" +
+		"     └──────\n" +
+		"      Se Oc \n"
+	if falling != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, falling)
+	}
+
+	if lines := strings.Count(lineChart([]int64{-500}, []string{"Oct"}, "-$5", "-$5", 3, 4, plain), "\n"); lines != 5 {
+		t.Errorf("expected a chart of a single value to have 5 lines, got %d", lines)
+	}
+}
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 )
 
@@ -15,9 +17,7 @@ type command struct {
 	name string
 }
 
-func (c *command) parse(handler *cmdHandler, input string) error {
-	cmdFields := cleanInput(input)
-	c.name = cmdFields[0]
+func (c *command) parse(handler *cmdHandler, cmdFields []string) error {
 	optionsToParse := handler.options
 	var parsingOption *cmdElement
 	optArgCountNeeded := 0
@@ -32,6 +32,10 @@ func (c *command) parse(handler *cmdHandler, input string) error {
 				len(input) == 2 && !strings.Contains("0123456789", string(input[1])))
 	}
 
+	// variadic is TRUE when the last expected parameter
+	// takes all remaining arguments
+	variadic := handler.variadic
+
 	// parametersSatisfied is TRUE when a user has supplied all
 	// arguments necessary given the command and subcommand they
 	// have otherwise written
@@ -47,8 +51,17 @@ func (c *command) parse(handler *cmdHandler, input string) error {
 				parsingOption = nil
 			}
 		} else {
+			// an option standing in for the parameters of the action
+			// satisfies them, being given in their place
+			if !parametersSatisfied && actionElement != nil && hasOptFormat(cmdFields[i]) {
+				if opt, found := findCMDElementWithName(actionElement.options, strings.TrimLeft(cmdFields[i], "-")); found && opt.standsIn {
+					c.opts[opt.name] = []string{"SET"}
+					parametersSatisfied = true
+					continue
+				}
+			}
 			// have we encountered a potential option we could take?
-			if parametersSatisfied && hasOptFormat(cmdFields[i]) {
+			if parametersSatisfied && !variadic && hasOptFormat(cmdFields[i]) {
 				// find out if the handler takes this option
 				userOpt := strings.TrimLeft(cmdFields[i], "-")
 				foundMatch := false
@@ -72,36 +85,38 @@ func (c *command) parse(handler *cmdHandler, input string) error {
 					if actionElement != nil {
 						optType = "action"
 					}
-					return fmt.Errorf("input command includes unexpected %s option '%s'", optType, cmdFields[i])
+					suggestion := didYouMean(userOpt, elementNames(optionsToParse))
+					return newCLIError(errKindUsage, "input command includes unexpected %s option '%s'%s", optType, cmdFields[i], suggestion)
 				} else {
 					continue
 				}
-			} else if parametersSatisfied {
-				return fmt.Errorf("input command includes unexpected argument '%s'", cmdFields[i])
+			} else if parametersSatisfied && !variadic {
+				return newCLIError(errKindUsage, "input command includes unexpected argument '%s'", cmdFields[i])
 			}
 			// not parsing an option; include in command argument stack
 			c.args = append(c.args, cmdFields[i])
 			// check whether or not we are in subcommand territory, where we begin parsing ITS options
-			if el, found := findCMDElementWithName(handler.actions, cmdFields[i]); found {
+			if el, found := findCMDElementWithName(handler.actions, cmdFields[i]); found && actionElement == nil {
 				actionElement = el
 				optionsToParse = el.options
+				variadic = el.variadic
 			}
 			if actionElement == nil {
-				parametersSatisfied = len(c.args) == handler.argCount()
+				parametersSatisfied = len(c.args) >= handler.argCount()
 			} else {
-				parametersSatisfied = len(c.args) == handler.argCount()+actionElement.argCount()
+				parametersSatisfied = len(c.args) >= handler.argCount()+actionElement.argCount()
 			}
 		}
 	}
 	if optArgCountNeeded > 0 {
-		return fmt.Errorf("command could not be parsed; missing positional argument(s) for option [%s]: <%s>", parsingOption.name, parsingOption.parameters[len(c.opts[parsingOption.name])])
+		return newCLIError(errKindUsage, "command could not be parsed; missing positional argument(s) for option [%s]: <%s>", parsingOption.name, parsingOption.parameters[len(c.opts[parsingOption.name])])
 	}
 	// if a command action was specified...
 	if actionElement != nil && !parametersSatisfied {
 		// check that the user has satisfied all parameters with arguments
 		expectedArgs := append(handler.parameters, actionElement.parameters...)
 		if len(c.args) < len(expectedArgs) {
-			return fmt.Errorf("command could not be parsed; missing positional argument: <%s>", expectedArgs[len(c.args)])
+			return newCLIError(errKindUsage, "command could not be parsed; missing positional argument: <%s>", expectedArgs[len(c.args)])
 		}
 	}
 	return nil
@@ -127,11 +142,20 @@ type cmdElement struct {
 	// A slice of expected arguments, in order, expected by this cmdElement.
 	parameters []string
 
+	// Alternative names by which this cmdElement may be referred to.
+	aliases []string
+
 	// Priority refers to an element's relevance to output.
 	// The lower the value, the higher the priority.
 	priority int
 	// whether or not this element is an option that may be treated as a flag
 	useShorthand bool
+	// whether or not this element is a flag that may be given in place
+	// of the parameters of its action, such that none are expected
+	standsIn bool
+	// whether or not the last of this element's parameters takes all
+	// remaining arguments, such that no further options are parsed
+	variadic bool
 }
 
 func (e *cmdElement) usage(withOptions bool) string {
@@ -162,6 +186,21 @@ func (e *cmdElement) usage(withOptions bool) string {
 	return usage.String()
 }
 
+// hasName is TRUE when the given name is either the
+// name of the element or one of its aliases.
+func (e *cmdElement) hasName(name string) bool {
+	return e.name == name || slices.Contains(e.aliases, name)
+}
+
+// nameWithAliases returns the element name,
+// followed by any aliases in parentheses.
+func (e *cmdElement) nameWithAliases() string {
+	if len(e.aliases) == 0 {
+		return e.name
+	}
+	return fmt.Sprintf("%s (%s)", e.name, strings.Join(e.aliases, ", "))
+}
+
 func (e *cmdElement) argCount() int {
 	return len(e.parameters)
 }
@@ -176,6 +215,78 @@ type handlerContext struct {
 	cmd       command
 	args      argTracker
 	ctxValues map[string]string
+	globals   globalOptions
+}
+
+// globalOptions are options which may accompany any command.
+// They are parsed out of user input before the command itself.
+type globalOptions struct {
+	output string
+	yes    bool // skip confirmation of destructive actions
+	dryRun bool // print API requests that mutate data instead of making them
+}
+
+// dryRun reports whether the command is a dry run. If it is, the
+// API request that would otherwise have been made is printed instead,
+// with any payload shown as the JSON that would be sent.
+func (c *handlerContext) dryRun(request string, args ...any) bool {
+	if !c.globals.dryRun {
+		return false
+	}
+	params := []string{}
+	payloads := []string{}
+	for _, arg := range args {
+		if str, ok := arg.(string); ok {
+			params = append(params, fmt.Sprintf("%q", str))
+			continue
+		}
+		data, err := json.MarshalIndent(arg, "  ", "  ")
+		if err != nil {
+			data = fmt.Appendf(nil, "%+v", arg)
+		}
+		params = append(params, "payload")
+		payloads = append(payloads, string(data))
+	}
+	fmt.Printf("DRY RUN: would request %s(%s)\n", request, strings.Join(params, ", "))
+	for _, payload := range payloads {
+		fmt.Println("  payload: " + payload)
+	}
+	return true
+}
+
+// parseGlobalOptions separates any global options from the given
+// command fields, returning them alongside the remaining fields. The
+// body of an alias being defined is left whole, as any global options
+// within it belong to the command that it stands for.
+func parseGlobalOptions(cmdFields []string) (globalOptions, []string, error) {
+	globals := globalOptions{output: "text"}
+	remaining := make([]string, 0, len(cmdFields))
+	for i := 0; i < len(cmdFields); i++ {
+		if len(remaining) == 2 && remaining[0] == "alias" {
+			remaining = append(remaining, cmdFields[i:]...)
+			break
+		}
+		switch cmdFields[i] {
+		case "--output":
+			if i+1 >= len(cmdFields) {
+				return globals, nil, newCLIError(errKindUsage, "missing positional argument for option [output]: <format>")
+			}
+			i++
+			switch cmdFields[i] {
+			case "text", "json":
+				globals.output = cmdFields[i]
+			default:
+				return globals, nil, newCLIError(errKindUsage, "invalid output format '%s'; use text or json", cmdFields[i])
+			}
+		case "--yes":
+			globals.yes = true
+		case "--dry-run":
+			globals.dryRun = true
+		default:
+			remaining = append(remaining, cmdFields[i])
+		}
+	}
+	return globals, remaining, nil
 }
 
 // ========== ARGUMENT TRACKING =============
@@ -218,6 +329,15 @@ func (a *argTracker) chooseIndex() (*int, *[]string) {
 	return &a.cmdArgIndex, a.cmdArgs
 }
 
+// return the value at the tracked index without postfixing it
+func (a *argTracker) peek() (string, error) {
+	index, args := a.chooseIndex()
+	if *index >= len(*args) {
+		return "", fmt.Errorf("index out of range")
+	}
+	return (*args)[*index], nil
+}
+
 // postfix the tracked index, returning current value
 func (a *argTracker) pfx() (string, error) {
 	index, args := a.chooseIndex()
@@ -250,6 +370,9 @@ type cmdHandler struct {
 
 func (c *cmdHandler) help() {
 	fmt.Println("COMMAND: " + c.name)
+	if len(c.aliases) > 0 {
+		fmt.Println("ALIASES: " + strings.Join(c.aliases, ", "))
+	}
 	fmt.Println(c.description)
 	fmt.Println("USAGE: " + c.usage(true))
 	if len(c.actions) > 0 {
@@ -259,7 +382,7 @@ func (c *cmdHandler) help() {
 		column1 := []string{}
 		column2 := []string{}
 		for _, action := range c.actions {
-			column1 = append(column1, fmt.Sprintf("  %s", action.name))
+			column1 = append(column1, fmt.Sprintf("  %s", action.nameWithAliases()))
 			column2 = append(column2, action.description)
 		}
 		fmt.Println(makeAlignedTable(column1, column2))
@@ -292,34 +415,37 @@ type commandRegistry struct {
 	handlers map[string]*cmdHandler
 }
 
-func (c *commandRegistry) run(s *State, input string) error {
+func (c *commandRegistry) run(s *State, globals globalOptions, cmdFields []string) error {
+	if len(cmdFields) == 0 {
+		return newCLIError(errKindUsage, "no command specified")
+	}
 	cmd := command{
-		name: cleanInput(input)[0],
+		name: c.resolve(cmdFields[0]),
 		opts: map[string][]string{},
 	}
 
 	// run the command only if it is fully registered
 	status, registered := c.registry[cmd.name]
 	if !registered {
-		return fmt.Errorf("unknown command '%s'", cmd.name)
+		return newCLIError(errKindUnknownCommand, "unknown command '%s'%s", cmd.name, didYouMean(cmd.name, c.names(s)))
 	}
 	switch status {
 	case Preregistered:
-		return fmt.Errorf("cannot execute command '%s': %s", cmd.name, c.handlers[cmd.name].nonRegMsg)
+		return newCLIError(errKindPrecondition, "cannot execute command '%s': %s", cmd.name, c.handlers[cmd.name].nonRegMsg)
 	case NotRegistered:
 		fallthrough
 	default:
-		return fmt.Errorf("unknown command '%s'", cmd.name)
+		return newCLIError(errKindUnknownCommand, "unknown command '%s'", cmd.name)
 	case Registered:
 		// command is registered for use, so it may be run
 	}
 
 	handler, registered := c.handlers[cmd.name]
-	if !registered {
+	if !registered || handler.callback == nil {
 		return fmt.Errorf("command found in registry, but without any handler")
 	}
 
-	err := cmd.parse(handler, input)
+	err := cmd.parse(handler, cmdFields)
 	if err != nil {
 		return err
 	}
@@ -328,10 +454,24 @@ func (c *commandRegistry) run(s *State, input string) error {
 		cmd:       cmd,
 		args:      argTracker{},
 		ctxValues: make(map[string]string),
+		globals:   globals,
 	}
 	context.args.init(&context.cmd)
 
-	return handler.callback(s, context)
+	err = handler.callback(s, context)
+
+	// forget the money left to assign shown in the prompt
+	// whenever a command might have changed it
+	action := context.ctxValues["action"]
+	if action == "" && len(cmd.args) > 0 {
+		if el, found := findCMDElementWithName(handler.actions, cmd.args[0]); found {
+			action = el.name
+		}
+	}
+	if !globals.dryRun && !leavesReadiness(cmd.name, action) {
+		s.Session.promptReady = nil
+	}
+	return err
 }
 
 // preregister establishes the existence of a command and its handler.
@@ -406,8 +546,38 @@ func (c *commandRegistry) deregisterNonBaseCommands() {
 	c.batchRegistration(makeBaseCommandHandlers(), Registered)
 }
 
+// resolve returns the name of the command which
+// goes by the given name or alias.
+func (c *commandRegistry) resolve(name string) string {
+	if _, ok := c.handlers[name]; ok {
+		return name
+	}
+	for cmdName, handler := range c.handlers {
+		if handler.hasName(name) {
+			return cmdName
+		}
+	}
+	return name
+}
+
+// names returns the names and aliases of all commands in the
+// registry, along with any aliases defined by the user.
+func (c *commandRegistry) names(s *State) []string {
+	names := []string{}
+	for _, handler := range c.handlers {
+		names = append(names, handler.name)
+		names = append(names, handler.aliases...)
+	}
+	if s != nil && s.Config != nil {
+		for alias := range s.Config.Aliases {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
 func (c *commandRegistry) exists(name string) (*cmdHandler, bool) {
-	handler, ok := c.handlers[name]
+	handler, ok := c.handlers[c.resolve(name)]
 	if ok {
 		return handler, ok
 	}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseStandInOption(t *testing.T) {
+	handler := &cmdHandler{
+		cmdElement: cmdElement{name: "category", parameters: []string{"action"}},
+		actions: []cmdElement{
+			{
+				name:       "assign",
+				parameters: []string{"category_name", "amount"},
+				options: []cmdElement{
+					{name: "fund-goals", standsIn: true},
+					{name: "month", parameters: []string{"YYYY-MM"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		fields       []string
+		expectedArgs []string
+		expectedOpts []string
+	}{
+		{
+			name:         "parameters given",
+			fields:       []string{"category", "assign", "Rent", "1200", "--month", "2026-10"},
+			expectedArgs: []string{"assign", "Rent", "1200"},
+			expectedOpts: []string{"month"},
+		},
+		{
+			name:         "stand-in in place of parameters",
+			fields:       []string{"category", "assign", "--fund-goals", "--month", "2026-10"},
+			expectedArgs: []string{"assign"},
+			expectedOpts: []string{"fund-goals", "month"},
+		},
+		{
+			name:         "stand-in along with parameters",
+			fields:       []string{"category", "assign", "Rent", "1200", "--fund-goals"},
+			expectedArgs: []string{"assign", "Rent", "1200"},
+			expectedOpts: []string{"fund-goals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := command{name: tt.fields[0], opts: map[string][]string{}}
+			if err := cmd.parse(handler, tt.fields); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(cmd.args, tt.expectedArgs) {
+				t.Errorf("expected args %q, got %q", tt.expectedArgs, cmd.args)
+			}
+			opts := []string{}
+			for opt := range cmd.opts {
+				opts = append(opts, opt)
+			}
+			slices.Sort(opts)
+			if !slices.Equal(opts, tt.expectedOpts) {
+				t.Errorf("expected options %q, got %q", tt.expectedOpts, opts)
+			}
+		})
+	}
+}
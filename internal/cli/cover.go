@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"cmp"
+	"slices"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// categoryBalance is the balance of a category at the end of a month.
+type categoryBalance struct {
+	category string
+	balance  cc.Money
+}
+
+// coverMove is money moved from one category
+// to cover overspending in another.
+type coverMove struct {
+	from   string
+	to     string
+	amount cc.Money
+}
+
+// findOverspending returns the categories overspent in a month, most
+// overspent first, along with those having money to spare, most first.
+func findOverspending(reports []*pgo.CategoryReport, ISOCode string) ([]categoryBalance, []categoryBalance) {
+	overspent, sources := []categoryBalance{}, []categoryBalance{}
+	for _, report := range reports {
+		balance := categoryBalance{category: report.Name, balance: cc.NewMoney(report.Balance, ISOCode)}
+		switch {
+		case balance.balance.IsNegative():
+			overspent = append(overspent, balance)
+		case balance.balance.IsPositive():
+			sources = append(sources, balance)
+		}
+	}
+	slices.SortFunc(overspent, func(a, b categoryBalance) int {
+		return cmp.Or(cmp.Compare(a.balance.Amount, b.balance.Amount), cmp.Compare(a.category, b.category))
+	})
+	slices.SortFunc(sources, func(a, b categoryBalance) int {
+		return cmp.Or(cmp.Compare(b.balance.Amount, a.balance.Amount), cmp.Compare(a.category, b.category))
+	})
+	return overspent, sources
+}
+
+// takeFrom plans a move covering as much of a shortfall as the source
+// can spare, taking it out of the source's balance. It returns the
+// move and the shortfall left after it.
+func takeFrom(source *categoryBalance, to string, shortfall cc.Money) (coverMove, cc.Money, error) {
+	amount := shortfall
+	isMore, err := amount.Cmp(source.balance)
+	if err != nil {
+		return coverMove{}, cc.Money{}, err
+	}
+	if isMore > 0 {
+		amount = source.balance
+	}
+	if source.balance, err = source.balance.Sub(amount); err != nil {
+		return coverMove{}, cc.Money{}, err
+	}
+	left, err := shortfall.Sub(amount)
+	if err != nil {
+		return coverMove{}, cc.Money{}, err
+	}
+	return coverMove{from: source.category, to: to, amount: amount}, left, nil
+}
+
+// mostToSpare returns the index of the source with the most money to
+// spare, reporting false if none has any left. Ties go to the first.
+func mostToSpare(sources []categoryBalance) (int, bool) {
+	most, found := 0, false
+	for i, source := range sources {
+		if source.balance.IsPositive() && (!found || source.balance.Amount > sources[most].balance.Amount) {
+			most, found = i, true
+		}
+	}
+	return most, found
+}
+
+// planCover plans moves covering each overspent category in turn, each
+// time from the source with the most to spare, until the sources run
+// dry. It returns the moves along with the overspending left uncovered.
+func planCover(overspent, sources []categoryBalance) ([]coverMove, []categoryBalance, error) {
+	sources = slices.Clone(sources)
+	moves := []coverMove{}
+	uncovered := []categoryBalance{}
+	for _, short := range overspent {
+		shortfall, err := short.balance.Neg()
+		if err != nil {
+			return nil, nil, err
+		}
+		for shortfall.IsPositive() {
+			most, ok := mostToSpare(sources)
+			if !ok {
+				break
+			}
+			var move coverMove
+			if move, shortfall, err = takeFrom(&sources[most], short.category, shortfall); err != nil {
+				return nil, nil, err
+			}
+			moves = append(moves, move)
+		}
+		if shortfall.IsPositive() {
+			uncovered = append(uncovered, categoryBalance{category: short.category, balance: shortfall})
+		}
+	}
+	return moves, uncovered, nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestFindOverspending(t *testing.T) {
+	reports := []*pgo.CategoryReport{
+		{Name: "Dining", Balance: -5000},
+		{Name: "Groceries", Balance: 12000},
+		{Name: "Fuel", Balance: -20000},
+		{Name: "Rent", Balance: 0},
+		{Name: "Gifts", Balance: 30000},
+	}
+	overspent, sources := findOverspending(reports, "USD")
+
+	names := func(balances []categoryBalance) []string {
+		out := []string{}
+		for _, balance := range balances {
+			out = append(out, balance.category)
+		}
+		return out
+	}
+	if !slices.Equal(names(overspent), []string{"Fuel", "Dining"}) {
+		t.Errorf("expected Fuel then Dining overspent, got %q", names(overspent))
+	}
+	if !slices.Equal(names(sources), []string{"Gifts", "Groceries"}) {
+		t.Errorf("expected Gifts then Groceries to spare, got %q", names(sources))
+	}
+}
+
+func TestPlanCover(t *testing.T) {
+	usd := func(amount int64) cc.Money { return cc.NewMoney(amount, "USD") }
+	overspent := []categoryBalance{
+		{category: "Fuel", balance: usd(-20000)},
+		{category: "Dining", balance: usd(-5000)},
+		{category: "Clothing", balance: usd(-30000)},
+	}
+	sources := []categoryBalance{
+		{category: "Gifts", balance: usd(15000)},
+		{category: "Groceries", balance: usd(12000)},
+	}
+
+	moves, uncovered, err := planCover(overspent, sources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedMoves := []coverMove{
+		{from: "Gifts", to: "Fuel", amount: usd(15000)},
+		{from: "Groceries", to: "Fuel", amount: usd(5000)},
+		{from: "Groceries", to: "Dining", amount: usd(5000)},
+		{from: "Groceries", to: "Clothing", amount: usd(2000)},
+	}
+	if !slices.Equal(moves, expectedMoves) {
+		t.Errorf("expected moves %+v, got %+v", expectedMoves, moves)
+	}
+	expectedUncovered := []categoryBalance{{category: "Clothing", balance: usd(28000)}}
+	if !slices.Equal(uncovered, expectedUncovered) {
+		t.Errorf("expected uncovered %+v, got %+v", expectedUncovered, uncovered)
+	}
+	if !sources[0].balance.Equal(usd(15000)) {
+		t.Error("expected the sources given to be left as they were")
+	}
+}
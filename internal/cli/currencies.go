@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/exchange"
+)
+
+// homeCurrency returns the ISO Code of the currency in which
+// amounts of the budget in view are kept, and into which
+// amounts of other currencies are converted for reports.
+func (s *State) homeCurrency() string {
+	return s.budgetCurrency(s.Session.ActiveBudget.ID.String())
+}
+
+// budgetCurrency returns the ISO Code of the currency in which the
+// given budget is kept. Budgets not given a currency of their own
+// use the one set in the user configuration.
+func (s *State) budgetCurrency(budgetID string) string {
+	if iso, ok := s.Config.BudgetCurrencies[budgetID]; ok {
+		return iso
+	}
+	return s.Config.CurrencyISOCode
+}
+
+// setBudgetCurrency records the currency in which the given budget is kept.
+func (s *State) setBudgetCurrency(budgetID, ISOCode string) error {
+	currency, err := cc.Lookup(ISOCode)
+	if err != nil {
+		return newCLIError(errKindUsage, "%v", err)
+	}
+	if s.Config.BudgetCurrencies == nil {
+		s.Config.BudgetCurrencies = map[string]string{}
+	}
+	s.Config.BudgetCurrencies[budgetID] = currency.ISOCode
+	err = s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save budget currency: %w", err)
+	}
+	return nil
+}
+
+// accountCurrency returns the ISO Code of the
+// currency in which the given account is kept.
+func (s *State) accountCurrency(accountID string) string {
+	if iso, ok := s.Config.AccountCurrencies[accountID]; ok {
+		return iso
+	}
+	return s.homeCurrency()
+}
+
+// budgetCurrencyKeyword may be given in place of an ISO Code to keep
+// an account in the currency of its budget, whatever that may become.
+const budgetCurrencyKeyword = "budget"
+
+// setAccountCurrency records the currency in which the given account is
+// kept. It is kept even if it is that of the budget, such that the
+// account stays in it should the currency of the budget change.
+func (s *State) setAccountCurrency(accountID, ISOCode string) error {
+	currency, err := cc.Lookup(ISOCode)
+	if err != nil {
+		return newCLIError(errKindUsage, "%v", err)
+	}
+	if s.Config.AccountCurrencies == nil {
+		s.Config.AccountCurrencies = map[string]string{}
+	}
+	s.Config.AccountCurrencies[accountID] = currency.ISOCode
+	err = s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save account currency: %w", err)
+	}
+	return nil
+}
+
+// resetAccountCurrency forgets the currency in which the given account
+// is kept, such that it is kept in that of its budget.
+func (s *State) resetAccountCurrency(accountID string) error {
+	delete(s.Config.AccountCurrencies, accountID)
+	err := s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save account currency: %w", err)
+	}
+	return nil
+}
+
+// conversion is an amount converted into the home currency.
+type conversion struct {
+	amount   cc.Money
+	rate     *big.Rat
+	rateDate string
+}
+
+// toHomeCurrency converts an amount of any currency into the
+// home currency, using the latest rate as of the given date. It
+// reports false if no such rate has been set.
+func (s *State) toHomeCurrency(rates *exchange.Table, amount cc.Money, on time.Time) (conversion, bool, error) {
+	home := s.homeCurrency()
+	rate, value, found := rates.Find(amount.ISOCode, home, on)
+	if !found {
+		return conversion{}, false, nil
+	}
+	converted, err := amount.Convert(home, value)
+	if err != nil {
+		return conversion{}, false, err
+	}
+	return conversion{amount: converted, rate: value, rateDate: rate.Date}, true, nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+)
+
+// dateParser returns a parser reading dates relative to the
+// present, in the local format of the user configuration.
+func (s *State) dateParser() (*dates.Parser, error) {
+	p, err := dates.NewParser(time.Now(), s.Config.DateFormat)
+	if err != nil {
+		return nil, newCLIError(errKindUsage, "%v; fix it with `config set date-format`", err)
+	}
+	return p, nil
+}
+
+// dayOption reads the day given to the named option,
+// defaulting to the present day.
+func (s *State) dayOption(c *handlerContext, option string) (time.Time, error) {
+	p, err := s.dateParser()
+	if err != nil {
+		return time.Time{}, err
+	}
+	c.args.trackOptArgs(&c.cmd, option)
+	input, _ := c.args.pfx()
+	if input == "" {
+		return p.Today(), nil
+	}
+	day, err := p.Day(input)
+	if err != nil {
+		return time.Time{}, newCLIError(errKindUsage, "%v", err)
+	}
+	return day, nil
+}
+
+// monthOption reads the month given to the named option,
+// defaulting to the present month. The month is given by its first day.
+func (s *State) monthOption(c *handlerContext, option string) (time.Time, error) {
+	p, err := s.dateParser()
+	if err != nil {
+		return time.Time{}, err
+	}
+	c.args.trackOptArgs(&c.cmd, option)
+	input, _ := c.args.pfx()
+	if input == "" {
+		input = "this month"
+	}
+	month, err := p.Month(input)
+	if err != nil {
+		return time.Time{}, newCLIError(errKindUsage, "%v", err)
+	}
+	return month, nil
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Exit codes returned by the process when running a single command
+// outside of the REPL, letting scripts react to the kind of failure.
+const (
+	ExitOK             = 0
+	ExitFailure        = 1
+	ExitUsage          = 2
+	ExitUnknownCommand = 3
+	ExitPrecondition   = 4
+	ExitNotFound       = 5
+	ExitAPI            = 6
+	ExitNetwork        = 7
+)
+
+type errorKind int
+
+const (
+	errKindGeneral errorKind = iota
+	errKindUnknownCommand
+	errKindPrecondition
+	errKindUsage
+	errKindNotFound
+	errKindAPI
+	errKindNetwork
+)
+
+func (k errorKind) String() string {
+	switch k {
+	case errKindUnknownCommand:
+		return "unknown_command"
+	case errKindPrecondition:
+		return "precondition"
+	case errKindUsage:
+		return "usage"
+	case errKindNotFound:
+		return "not_found"
+	case errKindAPI:
+		return "api"
+	case errKindNetwork:
+		return "network"
+	default:
+		return "general"
+	}
+}
+
+func (k errorKind) exitCode() int {
+	switch k {
+	case errKindUnknownCommand:
+		return ExitUnknownCommand
+	case errKindPrecondition:
+		return ExitPrecondition
+	case errKindUsage:
+		return ExitUsage
+	case errKindNotFound:
+		return ExitNotFound
+	case errKindAPI:
+		return ExitAPI
+	case errKindNetwork:
+		return ExitNetwork
+	default:
+		return ExitFailure
+	}
+}
+
+// cliError is an error that knows what kind of failure caused it,
+// so that it may be reported to scripts in a predictable manner.
+type cliError struct {
+	err    error
+	kind   errorKind
+	status int // HTTP status code, if the error came from the API
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// newCLIError formats an error as fmt.Errorf would,
+// tagging it with the given kind.
+func newCLIError(kind errorKind, format string, a ...any) error {
+	return &cliError{
+		err:  fmt.Errorf(format, a...),
+		kind: kind,
+	}
+}
+
+// statusCoder is satisfied by errors which carry
+// the HTTP status code of a failed API request.
+type statusCoder interface {
+	StatusCode() int
+}
+
+// classifyError returns the given error as a *cliError.
+// Errors that were not already tagged with a kind are
+// inspected for network failures and HTTP status codes.
+func classifyError(err error) *cliError {
+	if err == nil {
+		return nil
+	}
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		return cliErr
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &cliError{err: err, kind: errKindNetwork}
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		kind := errKindAPI
+		if sc.StatusCode() == 404 {
+			kind = errKindNotFound
+		}
+		return &cliError{err: err, kind: kind, status: sc.StatusCode()}
+	}
+	return &cliError{err: err, kind: errKindGeneral}
+}
+
+// ExitCode returns the process exit code corresponding to the given error.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return classifyError(err).kind.exitCode()
+}
+
+// printError writes an error to the terminal in the requested output format.
+func printError(err error, output string) {
+	cliErr := classifyError(err)
+	if output == "json" {
+		type jsonError struct {
+			Kind     string `json:"kind"`
+			Message  string `json:"message"`
+			ExitCode int    `json:"exit_code"`
+			Status   int    `json:"status,omitempty"`
+		}
+		data, jsonErr := json.Marshal(struct {
+			Error jsonError `json:"error"`
+		}{
+			Error: jsonError{
+				Kind:     cliErr.kind.String(),
+				Message:  cliErr.Error(),
+				ExitCode: cliErr.kind.exitCode(),
+				Status:   cliErr.status,
+			},
+		})
+		if jsonErr == nil {
+			fmt.Println(string(data))
+			return
+		}
+	}
+	fmt.Println("ERROR:", cliErr)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseGlobalOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []string
+		expectedOutput string
+		expectedFields []string
+		expectedYes    bool
+		expectedDryRun bool
+		wantErr        bool
+	}{
+		{
+			name:           "no global options",
+			input:          []string{"account", "list"},
+			expectedOutput: "text",
+			expectedFields: []string{"account", "list"},
+		},
+		{
+			name:           "json output",
+			input:          []string{"account", "delete", "Checking", "--output", "json"},
+			expectedOutput: "json",
+			expectedFields: []string{"account", "delete", "Checking"},
+		},
+		{
+			name:           "dry run without confirmation",
+			input:          []string{"--dry-run", "category", "delete", "Dining Out", "--yes"},
+			expectedOutput: "text",
+			expectedFields: []string{"category", "delete", "Dining Out"},
+			expectedYes:    true,
+			expectedDryRun: true,
+		},
+		{
+			name:           "alias body left whole",
+			input:          []string{"--dry-run", "alias", "nuke", "=", "budget", "delete", "X", "--yes"},
+			expectedOutput: "text",
+			expectedFields: []string{"alias", "nuke", "=", "budget", "delete", "X", "--yes"},
+			expectedDryRun: true,
+		},
+		{
+			name:    "missing format",
+			input:   []string{"account", "list", "--output"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			input:   []string{"--output", "xml", "account", "list"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globals, fields, err := parseGlobalOptions(tt.input)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if ExitCode(err) != ExitUsage {
+					t.Errorf("expected exit code %d, but got %d", ExitUsage, ExitCode(err))
+				}
+				return
+			}
+			if globals.output != tt.expectedOutput {
+				t.Errorf("expected output %s, but got %s", tt.expectedOutput, globals.output)
+			}
+			if globals.yes != tt.expectedYes || globals.dryRun != tt.expectedDryRun {
+				t.Errorf("expected yes: %v and dry run: %v, but got yes: %v and dry run: %v", tt.expectedYes, tt.expectedDryRun, globals.yes, globals.dryRun)
+			}
+			if fmt.Sprint(fields) != fmt.Sprint(tt.expectedFields) {
+				t.Errorf("expected fields %v, but got %v", tt.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{err: nil, expected: ExitOK},
+		{err: fmt.Errorf("something went wrong"), expected: ExitFailure},
+		{err: newCLIError(errKindUnknownCommand, "unknown command"), expected: ExitUnknownCommand},
+		{err: newCLIError(errKindPrecondition, "login required"), expected: ExitPrecondition},
+		{err: fmt.Errorf("wrapped: %w", newCLIError(errKindNotFound, "no accounts found")), expected: ExitNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.err), func(t *testing.T) {
+			if code := ExitCode(tt.err); code != tt.expected {
+				t.Fatalf("expected exit code %d, but got %d", tt.expected, code)
+			}
+		})
+	}
+}
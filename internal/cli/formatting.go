@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/term"
 )
@@ -15,11 +16,13 @@ func ExtractStrings[T any](items []T, f func(T) string) []string {
 	return strings
 }
 
+// MaxOfStrings returns the length in runes of the longest
+// string given, matching the widths used by fmt padding.
 func MaxOfStrings(s ...string) int {
 	maxLen := 0
 	for _, str := range s {
-		if len(str) > maxLen {
-			maxLen = len(str)
+		if n := utf8.RuneCountInString(str); n > maxLen {
+			maxLen = n
 		}
 	}
 	return maxLen
@@ -113,3 +116,47 @@ func makeAlignedTable(column1 []string, column2 []string) string {
 
 	return out.String()
 }
+
+// makeTable lays out rows beneath a header in columns as wide as their
+// widest cell, divided as in the other tables printed by the CLI. Widths
+// are counted in runes, which would count the escape codes of a styled
+// cell as well, so any cell painted in a style of the CLI must be kept
+// to the last column, which is never padded.
+func makeTable(headers []string, rows [][]string) string {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+		for _, row := range rows {
+			if i < len(row) {
+				widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+			}
+		}
+	}
+
+	var out strings.Builder
+	writeRow := func(cells []string, separator string) {
+		out.WriteString("  ")
+		for i := range headers {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			if i == len(headers)-1 {
+				out.WriteString(cell)
+				break
+			}
+			out.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + separator)
+		}
+		out.WriteByte('\n')
+	}
+	writeRow(headers, " | ")
+	dashes := make([]string, len(widths))
+	for i, width := range widths {
+		dashes[i] = nDashes(width)
+	}
+	out.WriteString("  " + strings.Join(dashes, "-+-") + "\n")
+	for _, row := range rows {
+		writeRow(row, "   ")
+	}
+	return out.String()
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestMakeTable(t *testing.T) {
+	headers := []string{"NAME", "BALANCE", "NOTES"}
+	rows := [][]string{
+		{"Checking", "€1.234,50", "main account"},
+		{"Cash", "$5.00"},
+	}
+	expected := "" +
+		"  NAME     | BALANCE   | NOTES\n" +
+		"  ---------+-----------+-------------\n" +
+		"  Checking   €1.234,50   main account\n" +
+		"  Cash       $5.00       \n"
+	if got := makeTable(headers, rows); got != expected {
+		t.Fatalf("expected table:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestMaxOfStrings(t *testing.T) {
+	if got := MaxOfStrings("$5.00", "€1.234,50", "abc"); got != 9 {
+		t.Fatalf("expected length 9 in runes, but got %d", got)
+	}
+}
@@ -2,8 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/exchange"
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
 )
 
@@ -19,7 +25,7 @@ func handlerAccount(s *State, c *handlerContext) error {
 		case "restore":
 			return handleAccountRestore(s, c)
 		case "delete":
-			return handleAccountDelete(s, c)
+			return middlewareConfirm(describeAccountDelete)(handleAccountDelete)(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -40,18 +46,49 @@ func handleAccountAdd(s *State, c *handlerContext) error {
 
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "currency")
+	iso, _ := c.args.pfx()
+	if iso != "" {
+		if _, err := cc.Lookup(iso); err != nil {
+			return newCLIError(errKindUsage, "%v", err)
+		}
+	}
 
-	err := s.Client.BudgetAccountCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetAccountCreateData{
+	payload := pgo.BudgetAccountCreateData{
 		MetaData: pgo.MetaData{
 			Name:  name,
 			Notes: notes,
 		},
 		AccountType: accountType,
-	})
+	}
+	if c.dryRun("BudgetAccountCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	aID, err := createFound(
+		func() ([]*pgo.Account, error) { return s.Client.BudgetAccounts(bID, "") },
+		func(a *pgo.Account) string { return a.ID.String() },
+		func() error { return s.Client.BudgetAccountCreate(bID, payload) },
+	)
 	if err != nil {
 		return fmt.Errorf("s.Client.BudgetAccountCreate: %w", err)
 	} else {
+		if aID != "" {
+			s.Session.UndoStack.push(
+				fmt.Sprintf("add account '%s' to budget %s", name, s.Session.ActiveBudget.Name),
+				undoAccountAdd(bID, aID),
+			)
+		}
 		fmt.Println("Account " + name + " successfully created as user: " + s.Session.ActiveUser.Username + ".")
+		if iso != "" {
+			if aID == "" {
+				return fmt.Errorf("could not set account currency: could not find the new account")
+			}
+			err = s.setAccountCurrency(aID, iso)
+			if err != nil {
+				return err
+			}
+		}
 		fmt.Println("See it with: `account list`")
 		return nil
 	}
@@ -60,6 +97,8 @@ func handleAccountAdd(s *State, c *handlerContext) error {
 func handleAccountList(s *State, c *handlerContext) error {
 	c.args.trackOptArgs(&c.cmd, "deleted")
 	listDeleted, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "balances")
+	listBalances, _ := c.args.pfx()
 
 	listDeletedQuery := ""
 	if listDeleted == "SET" {
@@ -82,6 +121,9 @@ func handleAccountList(s *State, c *handlerContext) error {
 	sort.Slice(accounts, func(i, j int) bool {
 		return accounts[i].Name < accounts[j].Name
 	})
+	if listBalances == "SET" {
+		return s.listAccountBalances(accounts)
+	}
 	const uuidLength = 36
 	maxLenName := MaxOfStrings(ExtractStrings(accounts, func(b *pgo.Account) string { return b.Name })...)
 	maxLenNotes := MaxOfStrings(ExtractStrings(accounts, func(b *pgo.Account) string { return b.Notes })...)
@@ -101,41 +143,136 @@ func handleAccountUpdate(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	account, err := findAccountByName(accountName, accounts)
+	account, err := resolveAccount(accountName, accounts)
 	if err != nil {
 		return err
 	}
 
+	// the currency is kept locally, so the server
+	// need only be told of changes to anything else
+	remoteChanges := false
 	c.args.trackOptArgs(&c.cmd, "name")
 	payloadName, err := c.args.pfx()
 	if err != nil {
 		payloadName = account.Name
+	} else {
+		remoteChanges = true
 	}
 	c.args.trackOptArgs(&c.cmd, "type")
 	payloadAccountType, err := c.args.pfx()
 	if err != nil {
 		payloadAccountType = account.AccountType
+	} else {
+		remoteChanges = true
 	}
 	c.args.trackOptArgs(&c.cmd, "notes")
 	payloadNotes, err := c.args.pfx()
 	if err != nil {
 		payloadNotes = account.Notes
+	} else {
+		remoteChanges = true
+	}
+	c.args.trackOptArgs(&c.cmd, "currency")
+	iso, _ := c.args.pfx()
+	if iso != "" {
+		if _, err := cc.Lookup(iso); err != nil && !strings.EqualFold(iso, budgetCurrencyKeyword) {
+			return newCLIError(errKindUsage, "%v; or use '%s' to keep it in the currency of its budget", err, budgetCurrencyKeyword)
+		}
+		if !remoteChanges {
+			return s.updateAccountCurrency(c, account, iso)
+		}
 	}
 
-	err = s.Client.BudgetAccountUpdate(s.Session.ActiveBudget.ID.String(), account.ID.String(), pgo.BudgetAccountUpdateData{
+	payload := pgo.BudgetAccountUpdateData{
 		MetaData: pgo.MetaData{
 			Name:  payloadName,
 			Notes: payloadNotes,
 		},
 		AccountType: payloadAccountType,
-	})
+	}
+	if c.dryRun("BudgetAccountUpdate", s.Session.ActiveBudget.ID.String(), account.ID.String(), payload) {
+		if iso != "" {
+			return s.updateAccountCurrency(c, account, iso)
+		}
+		return nil
+	}
+	err = s.Client.BudgetAccountUpdate(s.Session.ActiveBudget.ID.String(), account.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	undoInfo := undoAccountUpdate(s.Session.ActiveBudget.ID.String(), *account)
+	description := fmt.Sprintf("update account '%s' in budget %s", account.Name, s.Session.ActiveBudget.Name)
 	fmt.Println("Account updated with new information")
+	if iso == "" {
+		s.Session.UndoStack.push(description, undoInfo)
+		return nil
+	}
+
+	// both changes are undone together, as they were made by one command
+	undoCurrency, err := s.changeAccountCurrency(account, iso)
+	if err != nil {
+		s.Session.UndoStack.push(description, undoInfo)
+		return err
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("%s and set its currency to %s", description, s.accountCurrency(account.ID.String())),
+		func(s *State) error {
+			err := undoCurrency(s)
+			if err != nil {
+				return err
+			}
+			return undoInfo(s)
+		},
+	)
 	return nil
 }
 
+// updateAccountCurrency changes the currency in which an existing
+// account is kept, or resets it to that of its budget if given the
+// budget currency keyword, making the change undoable.
+func (s *State) updateAccountCurrency(c *handlerContext, account *pgo.Account, ISOCode string) error {
+	if c.globals.dryRun {
+		if strings.EqualFold(ISOCode, budgetCurrencyKeyword) {
+			fmt.Printf("DRY RUN: would keep account '%s' in the currency of its budget\n", account.Name)
+		} else {
+			fmt.Printf("DRY RUN: would set currency of account '%s' to %s\n", account.Name, strings.ToUpper(ISOCode))
+		}
+		return nil
+	}
+	undo, err := s.changeAccountCurrency(account, ISOCode)
+	if err != nil {
+		return err
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("set currency of account '%s' to %s", account.Name, s.accountCurrency(account.ID.String())),
+		undo,
+	)
+	return nil
+}
+
+// changeAccountCurrency changes the currency in which an existing
+// account is kept, returning the means to change it back.
+func (s *State) changeAccountCurrency(account *pgo.Account, ISOCode string) (func(s *State) error, error) {
+	aID := account.ID.String()
+	previous, wasSet := s.Config.AccountCurrencies[aID]
+	var err error
+	if strings.EqualFold(ISOCode, budgetCurrencyKeyword) {
+		err = s.resetAccountCurrency(aID)
+	} else {
+		err = s.setAccountCurrency(aID, ISOCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Account %s is now kept in %s\n", account.Name, s.accountCurrency(aID))
+	return func(s *State) error {
+		if wasSet {
+			return s.setAccountCurrency(aID, previous)
+		}
+		return s.resetAccountCurrency(aID)
+	}, nil
+}
+
 func handleAccountRestore(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 
@@ -143,19 +280,56 @@ func handleAccountRestore(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	account, err := findAccountByName(name, accounts)
+	account, err := resolveAccount(name, accounts)
 	if err != nil {
 		return err
 	}
 
+	if c.dryRun("BudgetAccountRestore", s.Session.ActiveBudget.ID.String(), account.ID.String()) {
+		return nil
+	}
 	err = s.Client.BudgetAccountRestore(s.Session.ActiveBudget.ID.String(), account.ID.String())
 	if err != nil {
 		return err
 	}
+	bID, aID := s.Session.ActiveBudget.ID.String(), account.ID.String()
+	s.Session.UndoStack.push(
+		fmt.Sprintf("restore account '%s' in budget %s", account.Name, s.Session.ActiveBudget.Name),
+		func(s *State) error {
+			return s.Client.BudgetAccountDelete(bID, aID, pgo.BudgetAccountDeleteData{DeleteHard: false})
+		},
+	)
 	fmt.Println("Account restored.")
 	return nil
 }
 
+// describeAccountDelete asks for confirmation only of hard
+// deletions, as soft-deleted accounts may be restored.
+func describeAccountDelete(s *State, c *handlerContext) (*destruction, error) {
+	if _, deleteHard := c.cmd.opts["hard"]; !deleteHard {
+		return nil, nil
+	}
+	name, _ := c.args.peek()
+
+	accounts, err := s.GetAccounts(s.Session.ActiveBudget.ID.String(), "?deleted")
+	if err != nil {
+		return nil, err
+	}
+	account, err := resolveAccount(name, accounts)
+	if err != nil {
+		return nil, err
+	}
+	txnCount, err := s.countTxns(s.Session.ActiveBudget.ID.String(), url.Values{"account_name": {account.Name}})
+	if err != nil {
+		return nil, err
+	}
+	return &destruction{
+		kind:    "account",
+		name:    account.Name,
+		effects: []string{fmt.Sprintf("%d transaction(s) in the account will be deleted", txnCount)},
+	}, nil
+}
+
 func handleAccountDelete(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 	c.args.trackOptArgs(&c.cmd, "hard")
@@ -171,21 +345,116 @@ func handleAccountDelete(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	account, err := findAccountByName(name, accounts)
+	account, err := resolveAccount(name, accounts)
 	if err != nil {
 		return err
 	}
 
-	err = s.Client.BudgetAccountDelete(s.Session.ActiveBudget.ID.String(), account.ID.String(), pgo.BudgetAccountDeleteData{
+	payload := pgo.BudgetAccountDeleteData{
 		DeleteHard: deleteHard,
-	})
+	}
+	if c.dryRun("BudgetAccountDelete", s.Session.ActiveBudget.ID.String(), account.ID.String(), payload) {
+		return nil
+	}
+	err = s.Client.BudgetAccountDelete(s.Session.ActiveBudget.ID.String(), account.ID.String(), payload)
 	if err != nil {
 		return err
 	}
 	if deleteHard {
 		fmt.Println("Account deleted. It cannot be restored.")
 	} else {
+		bID, aID := s.Session.ActiveBudget.ID.String(), account.ID.String()
+		s.Session.UndoStack.push(
+			fmt.Sprintf("delete account '%s' in budget %s", account.Name, s.Session.ActiveBudget.Name),
+			func(s *State) error {
+				return s.Client.BudgetAccountRestore(bID, aID)
+			},
+		)
 		fmt.Println("Account is deleted. It may be restored, or permanently deleted.")
 	}
 	return nil
 }
+
+// undoAccountAdd returns a function which deletes the account with the
+// given ID from the given budget. It is only soft-deleted, such that any
+// transactions logged in it since it was added may still be restored.
+func undoAccountAdd(bID, aID string) func(*State) error {
+	return func(s *State) error {
+		return s.Client.BudgetAccountDelete(bID, aID, pgo.BudgetAccountDeleteData{DeleteHard: false})
+	}
+}
+
+// undoAccountUpdate returns a function which rewrites
+// an account with its information prior to an update.
+func undoAccountUpdate(bID string, prior pgo.Account) func(*State) error {
+	return func(s *State) error {
+		return s.Client.BudgetAccountUpdate(bID, prior.ID.String(), pgo.BudgetAccountUpdateData{
+			MetaData: pgo.MetaData{
+				Name:  prior.Name,
+				Notes: prior.Notes,
+			},
+			AccountType: prior.AccountType,
+		})
+	}
+}
+
+// listAccountBalances prints the balance of each account in the
+// currency it is kept in, converting balances of foreign accounts
+// into the home currency with the latest exchange rate.
+func (s *State) listAccountBalances(accounts []*pgo.Account) error {
+	rates, err := exchange.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	home := s.homeCurrency()
+	now := time.Now()
+
+	total := cc.NewMoney(0, home)
+	missingRates := []string{}
+	rows := [][]string{}
+	for _, account := range accounts {
+		query := "?" + url.Values{"account_name": {account.Name}}.Encode()
+		txns, err := s.GetTxnsDetails(s.Session.ActiveBudget.ID.String(), query)
+		if err != nil {
+			return err
+		}
+		iso := s.accountCurrency(account.ID.String())
+		balance := cc.NewMoney(0, iso)
+		for _, txn := range txns {
+			balance, err = balance.Add(cc.NewMoney(txn.TotalAmount, iso))
+			if err != nil {
+				return fmt.Errorf("could not total balance of account '%s': %w", account.Name, err)
+			}
+		}
+
+		row := []string{account.Name, iso, balance.String(), "", ""}
+		converted, found, err := s.toHomeCurrency(rates, balance, now)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !found:
+			row[3] = "no rate"
+			missingRates = append(missingRates, iso)
+		default:
+			row[3] = converted.amount.String()
+			if iso != home {
+				row[4] = fmt.Sprintf("%s @ %s", converted.rateDate, converted.rate.FloatString(4))
+			}
+			total, err = total.Add(converted.amount)
+			if err != nil {
+				return fmt.Errorf("could not total balances: %w", err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	fmt.Printf("Account balances under budget %s: \n", s.Session.ActiveBudget.Name)
+	fmt.Print(makeTable([]string{"NAME", "CURRENCY", "BALANCE", "IN " + home, "RATE"}, rows))
+	fmt.Printf("  Total: %s\n", total)
+	if len(missingRates) > 0 {
+		slices.Sort(missingRates)
+		fmt.Printf("  Excludes balances without a rate to %s; set one with: `rate set %s %s <rate>`\n", home, slices.Compact(missingRates)[0], home)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// positionalArg matches placeholders like $1 within an alias,
+// to be substituted by arguments given to the alias.
+var positionalArg = regexp.MustCompile(`\$(\d+)`)
+
+// handlerAlias lists user-defined aliases when given no arguments,
+// and shows a single alias when given only its name. Otherwise, it
+// saves the remaining arguments as the command the alias stands for.
+func handlerAlias(s *State, c *handlerContext) error {
+	name, err := c.args.pfx()
+	if err != nil {
+		return listAliases(s)
+	}
+
+	expansion := []string{}
+	for arg, err := c.args.pfx(); err == nil; arg, err = c.args.pfx() {
+		expansion = append(expansion, arg)
+	}
+	if len(expansion) > 0 && expansion[0] == "=" {
+		expansion = expansion[1:]
+	}
+
+	if len(expansion) == 0 {
+		if cmd, ok := s.Config.Aliases[name]; ok {
+			fmt.Printf("alias %s = %s\n", name, cmd)
+			return nil
+		}
+		return newCLIError(errKindNotFound, "no alias found with provided name '%s'", name)
+	}
+
+	if strings.HasPrefix(name, "-") || strings.ContainsAny(name, " $=") {
+		return newCLIError(errKindUsage, "invalid alias name '%s'", name)
+	}
+	if _, exists := s.Session.CommandRegistry.exists(name); exists || name == "exit" {
+		return newCLIError(errKindUsage, "cannot alias '%s'; a command already goes by that name", name)
+	}
+	if _, exists := s.Session.CommandRegistry.exists(expansion[0]); !exists {
+		return newCLIError(errKindUsage, "an alias must stand for a known command, not '%s'", expansion[0])
+	}
+
+	if s.Config.Aliases == nil {
+		s.Config.Aliases = map[string]string{}
+	}
+	s.Config.Aliases[name] = joinFields(expansion)
+	err = s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save alias: %w", err)
+	}
+	fmt.Printf("Saved alias: %s = %s\n", name, s.Config.Aliases[name])
+	return nil
+}
+
+func handlerUnalias(s *State, c *handlerContext) error {
+	name, _ := c.args.pfx()
+	if _, ok := s.Config.Aliases[name]; !ok {
+		return newCLIError(errKindNotFound, "no alias found with provided name '%s'", name)
+	}
+	delete(s.Config.Aliases, name)
+	err := s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not remove alias: %w", err)
+	}
+	fmt.Printf("Removed alias: %s\n", name)
+	return nil
+}
+
+func listAliases(s *State) error {
+	if len(s.Config.Aliases) == 0 {
+		fmt.Println("No aliases defined.")
+		fmt.Println("For help defining one, see: `help alias`")
+		return nil
+	}
+	fmt.Println("ALIASES: ")
+	fmt.Println(makeAliasTable(s.Config.Aliases))
+	return nil
+}
+
+func makeAliasTable(aliases map[string]string) string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	column1 := []string{}
+	column2 := []string{}
+	for _, name := range names {
+		column1 = append(column1, fmt.Sprintf("  %s", name))
+		column2 = append(column2, aliases[name])
+	}
+	return makeAlignedTable(column1, column2)
+}
+
+// expandAlias replaces a user-defined alias at the start of the given
+// command fields with the command that it stands for, substituting
+// placeholders ($1, $2, ...) with the fields that followed the alias.
+// Fields not used for substitution are appended to the command.
+func (s *State) expandAlias(cmdFields []string) ([]string, error) {
+	if len(cmdFields) == 0 || s.Config == nil {
+		return cmdFields, nil
+	}
+	aliasName := cmdFields[0]
+	cmd, ok := s.Config.Aliases[aliasName]
+	if !ok {
+		return cmdFields, nil
+	}
+
+	args := cmdFields[1:]
+	used := make([]bool, len(args))
+	expanded := []string{}
+	var err error
+	for _, field := range cleanInput(cmd) {
+		field = positionalArg.ReplaceAllStringFunc(field, func(placeholder string) string {
+			n, _ := strconv.Atoi(placeholder[1:])
+			if n < 1 || n > len(args) {
+				err = newCLIError(errKindUsage, "alias '%s' expects an argument for %s", aliasName, placeholder)
+				return placeholder
+			}
+			used[n-1] = true
+			return args[n-1]
+		})
+		expanded = append(expanded, field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, arg := range args {
+		if !used[i] {
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, nil
+}
+
+// joinFields is the inverse of cleanInput, quoting
+// any fields that would otherwise be split apart.
+func joinFields(fields []string) string {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		if field == "" || strings.ContainsAny(field, " \t") {
+			field = `"` + field + `"`
+		}
+		quoted[i] = field
+	}
+	return strings.Join(quoted, " ")
+}
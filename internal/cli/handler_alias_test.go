@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/YouWantToPinch/pincher-cli/internal/config"
+)
+
+func TestExpandAlias(t *testing.T) {
+	s := &State{Config: &config.Config{Aliases: map[string]string{
+		"coffee": `txn log Checking "Blue Bottle" $1 "Dining Out"`,
+		"accts":  "account list",
+	}}}
+
+	tests := []struct {
+		input    []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			input:    []string{"coffee", "4.50"},
+			expected: []string{"txn", "log", "Checking", "Blue Bottle", "4.50", "Dining Out"},
+		},
+		{
+			input:    []string{"coffee", "4.50", "--notes", "oat milk"},
+			expected: []string{"txn", "log", "Checking", "Blue Bottle", "4.50", "Dining Out", "--notes", "oat milk"},
+		},
+		{
+			input:    []string{"accts", "-d"},
+			expected: []string{"account", "list", "-d"},
+		},
+		{
+			input:    []string{"account", "list"},
+			expected: []string{"account", "list"},
+		},
+		{
+			input:   []string{"coffee"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.input), func(t *testing.T) {
+			actual, err := s.expandAlias(tt.input)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v", tt.wantErr, err)
+			}
+			if fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", tt.expected) && !tt.wantErr {
+				t.Errorf("expected fields %q, but got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestJoinFields(t *testing.T) {
+	fields := []string{"txn", "log", "Checking", "Blue Bottle", "$1", ""}
+	joined := joinFields(fields)
+	if fmt.Sprintf("%q", cleanInput(joined)) != fmt.Sprintf("%q", fields) {
+		t.Fatalf("expected %q to split back into %q, but got %q", joined, fields, cleanInput(joined))
+	}
+}
+
+func TestAliasKeepsGlobalOptions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s := &State{Config: &config.Config{}}
+	s.NewSession()
+
+	err := s.runCommand([]string{"alias", "nuke", "=", "budget", "delete", "X", "--yes", "--dry-run"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "budget delete X --yes --dry-run"; s.Config.Aliases["nuke"] != expected {
+		t.Errorf("expected alias body %q, but got %q", expected, s.Config.Aliases["nuke"])
+	}
+}
@@ -2,12 +2,18 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/YouWantToPinch/pincher-cli/internal/config"
 	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+	"golang.org/x/term"
 )
 
 func handlerBudget(s *State, c *handlerContext) error {
@@ -24,7 +30,11 @@ func handlerBudget(s *State, c *handlerContext) error {
 		case "update":
 			return handleBudgetUpdate(s, c)
 		case "delete":
-			return handleBudgetDelete(s, c)
+			return middlewareConfirm(describeBudgetDelete)(handleBudgetDelete)(s, c)
+		case "autoassign":
+			return handleBudgetAutoassign(s, c)
+		case "cover":
+			return handleBudgetCover(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -38,18 +48,48 @@ func handleBudgetAdd(s *State, c *handlerContext) error {
 
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "currency")
+	iso, _ := c.args.pfx()
+	if iso != "" {
+		if _, err := cc.Lookup(iso); err != nil {
+			return newCLIError(errKindUsage, "%v", err)
+		}
+	}
 
-	err := s.Client.BudgetCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetCreateData{
+	payload := pgo.BudgetCreateData{
 		MetaData: pgo.MetaData{
 			Name:  name,
 			Notes: notes,
 		},
-	})
+	}
+	if c.dryRun("BudgetCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	bID, err := createFound(
+		func() ([]*pgo.Budget, error) { return s.Client.Budgets("", "") },
+		func(b *pgo.Budget) string { return b.ID.String() },
+		func() error { return s.Client.BudgetCreate(s.Session.ActiveBudget.ID.String(), payload) },
+	)
 	if err != nil {
 		return err
 	}
 
+	if bID != "" {
+		s.Session.UndoStack.push(
+			fmt.Sprintf("add budget '%s'", name),
+			func(s *State) error { return s.Client.BudgetDelete(bID) },
+		)
+	}
 	fmt.Println("Budget " + name + " successfully created as user: " + s.Session.ActiveUser.Username + ".")
+	if iso != "" {
+		if bID == "" {
+			return fmt.Errorf("could not set budget currency: could not find the new budget")
+		}
+		err = s.setBudgetCurrency(bID, iso)
+		if err != nil {
+			return err
+		}
+	}
 	fmt.Println("See it with: `budget view`")
 	return nil
 }
@@ -62,7 +102,7 @@ func handleBudgetView(s *State, c *handlerContext) error {
 		return fmt.Errorf("could not view specified budget: %w", err)
 	}
 
-	budget, err := findBudgetByName(name, budgets)
+	budget, err := resolveBudget(name, budgets)
 	if err != nil {
 		return err
 	}
@@ -71,29 +111,29 @@ func handleBudgetView(s *State, c *handlerContext) error {
 	// as the cache by nature may change at a moment's notice
 	s.Session.ActiveBudget = *budget
 	s.Session.OnViewBudget()
-	fmt.Printf("Now viewing budget: %s\n", budget.Name)
+	fmt.Printf("Now viewing budget: %s (amounts in %s)\n", budget.Name, s.homeCurrency())
 	return nil
 }
 
 func handleBudgetReport(s *State, c *handlerContext) error {
-	var err error
-
-	monthTime := time.Now()
-	c.args.trackOptArgs(&c.cmd, "month")
-	month, _ := c.args.pfx()
-	if month != "" {
-		monthTime, err = time.Parse("2006-01", month)
-		if err != nil {
-			return fmt.Errorf("bad month format; use YYYY-MM")
-		}
+	months, isRange, err := s.monthRange(c)
+	if err != nil {
+		return err
+	}
+	if isRange {
+		return s.printReportMatrix(c, months)
+	}
+	monthTime, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
 	}
-	monthStr := monthTime.Format("2006-01-02")
+	monthStr := monthTime.Format(dates.DayLayout)
 
 	report, err := s.Client.BudgetReport(s.Session.ActiveBudget.ID.String(), monthStr)
 	if err != nil {
 		return err
 	}
-	iso := s.Config.CurrencyISOCode
+	iso := s.homeCurrency()
 	assigned := cc.Format(report.Assigned, iso, true)
 	activity := cc.Format(report.Activity, iso, true)
 	balance := cc.Format(report.Balance, iso, true)
@@ -102,6 +142,12 @@ func handleBudgetReport(s *State, c *handlerContext) error {
 	fmt.Printf("  %s-+-%s-+-%s\n", nDashes(len("ASSIGNED")), nDashes(len("ACTIVITY")), nDashes(len("BALANCE")))
 	fmt.Printf("  %-*s | %-*s | %s\n", len(assigned), assigned, len(activity), activity, balance)
 	fmt.Printf("  %s-+-%s-+-%s\n", nDashes(len(assigned)), nDashes(len(activity)), nDashes(len(balance)))
+
+	readiness, err := s.readyToAssign(monthTime, report)
+	if err != nil {
+		return fmt.Errorf("could not work out money ready to assign: %w", err)
+	}
+	s.printReadiness(readiness)
 	return nil
 }
 
@@ -153,35 +199,153 @@ func handleBudgetUpdate(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	budget, err := findBudgetByName(budgetName, budgets)
+	budget, err := resolveBudget(budgetName, budgets)
 	if err != nil {
 		return err
 	}
 
+	// the currency is kept locally, so the server
+	// need only be told of changes to anything else
+	remoteChanges := false
 	c.args.trackOptArgs(&c.cmd, "name")
 	payloadName, err := c.args.pfx()
 	if err != nil {
 		payloadName = budget.Name
+	} else {
+		remoteChanges = true
 	}
 	c.args.trackOptArgs(&c.cmd, "notes")
 	payloadNotes, err := c.args.pfx()
 	if err != nil {
 		payloadNotes = budget.Notes
+	} else {
+		remoteChanges = true
+	}
+	c.args.trackOptArgs(&c.cmd, "currency")
+	iso, _ := c.args.pfx()
+	if iso != "" {
+		if _, err := cc.Lookup(iso); err != nil {
+			return newCLIError(errKindUsage, "%v", err)
+		}
+		if !remoteChanges {
+			return s.updateBudgetCurrency(c, budget, iso)
+		}
 	}
 
-	err = s.Client.BudgetUpdate(budget.ID.String(), pgo.BudgetCreateData{
+	payload := pgo.BudgetCreateData{
 		MetaData: pgo.MetaData{
 			Name:  payloadName,
 			Notes: payloadNotes,
 		},
-	})
+	}
+	if c.dryRun("BudgetUpdate", budget.ID.String(), payload) {
+		if iso != "" {
+			return s.updateBudgetCurrency(c, budget, iso)
+		}
+		return nil
+	}
+	err = s.Client.BudgetUpdate(budget.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	prior := *budget
+	undoInfo := func(s *State) error {
+		return s.Client.BudgetUpdate(prior.ID.String(), pgo.BudgetCreateData{
+			MetaData: pgo.MetaData{
+				Name:  prior.Name,
+				Notes: prior.Notes,
+			},
+		})
+	}
 	fmt.Println("Budget info updated with new information")
+	if iso == "" {
+		s.Session.UndoStack.push(fmt.Sprintf("update budget '%s'", budget.Name), undoInfo)
+		return nil
+	}
+
+	// both changes are undone together, as they were made by one command
+	undoCurrency, err := s.changeBudgetCurrency(budget, iso)
+	if err != nil {
+		s.Session.UndoStack.push(fmt.Sprintf("update budget '%s'", budget.Name), undoInfo)
+		return err
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("update budget '%s' and set its currency to %s", budget.Name, s.budgetCurrency(budget.ID.String())),
+		func(s *State) error {
+			err := undoCurrency(s)
+			if err != nil {
+				return err
+			}
+			return undoInfo(s)
+		},
+	)
 	return nil
 }
 
+// updateBudgetCurrency changes the currency in which
+// an existing budget is kept, making the change undoable.
+func (s *State) updateBudgetCurrency(c *handlerContext, budget *pgo.Budget, ISOCode string) error {
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would set currency of budget '%s' to %s\n", budget.Name, strings.ToUpper(ISOCode))
+		return nil
+	}
+	undo, err := s.changeBudgetCurrency(budget, ISOCode)
+	if err != nil {
+		return err
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("set currency of budget '%s' to %s", budget.Name, s.budgetCurrency(budget.ID.String())),
+		undo,
+	)
+	return nil
+}
+
+// changeBudgetCurrency changes the currency in which an existing
+// budget is kept, returning the means to change it back.
+func (s *State) changeBudgetCurrency(budget *pgo.Budget, ISOCode string) (func(s *State) error, error) {
+	bID := budget.ID.String()
+	previous, hadCurrency := s.Config.BudgetCurrencies[bID]
+	err := s.setBudgetCurrency(bID, ISOCode)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Budget %s is now kept in %s\n", budget.Name, s.budgetCurrency(bID))
+	return func(s *State) error {
+		if hadCurrency {
+			return s.setBudgetCurrency(bID, previous)
+		}
+		delete(s.Config.BudgetCurrencies, bID)
+		return s.Config.WriteToFile()
+	}, nil
+}
+
+func describeBudgetDelete(s *State, c *handlerContext) (*destruction, error) {
+	name, _ := c.args.peek()
+
+	budgets, err := s.GetBudgets(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	budget, err := resolveBudget(name, budgets)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.GetAccounts(budget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	// transactions are not counted, as that would mean
+	// fetching every one of them in the budget
+	return &destruction{
+		kind: "budget",
+		name: budget.Name,
+		effects: []string{
+			fmt.Sprintf("%d account(s) and all of their transactions will be deleted", len(accounts)),
+			"all of its categories, groups and payees will be deleted",
+		},
+	}, nil
+}
+
 func handleBudgetDelete(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 
@@ -189,15 +353,331 @@ func handleBudgetDelete(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	budget, err := findBudgetByName(name, budgets)
+	budget, err := resolveBudget(name, budgets)
 	if err != nil {
 		return err
 	}
 
+	if c.dryRun("BudgetDelete", budget.ID.String()) {
+		return nil
+	}
 	err = s.Client.BudgetDelete(budget.ID.String())
 	if err != nil {
 		return err
 	}
+	if _, ok := s.Config.BudgetCurrencies[budget.ID.String()]; ok {
+		delete(s.Config.BudgetCurrencies, budget.ID.String())
+		err = s.Config.WriteToFile()
+		if err != nil {
+			return fmt.Errorf("could not forget budget currency: %w", err)
+		}
+	}
 	fmt.Println("Budget deleted.")
 	return nil
 }
+
+func handleBudgetAutoassign(s *State, c *handlerContext) error {
+	strategy, _ := c.args.pfx()
+	if s.Session.ActiveBudget.Name == "" {
+		return newCLIError(errKindPrecondition, "first view a budget to assign within it")
+	}
+	strategies := []string{"template"}
+	for name := range assignStrategies {
+		strategies = append(strategies, name)
+	}
+	sort.Strings(strategies)
+	strategy = strings.ToLower(strategy)
+	if !slices.Contains(strategies, strategy) {
+		return newCLIError(errKindUsage, "unknown strategy '%s'%s", strategy, didYouMean(strategy, strategies))
+	}
+	month, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
+	}
+	monthStr := month.Format(dates.DayLayout)
+	bID := s.Session.ActiveBudget.ID.String()
+	iso := s.homeCurrency()
+
+	categories, err := s.GetCategories(bID, "")
+	if err != nil {
+		return err
+	}
+	plan, err := s.assignPlan(c, strategy, month, categories)
+	if err != nil {
+		return err
+	}
+
+	c.args.trackOptArgs(&c.cmd, "save-template")
+	if name, _ := c.args.pfx(); name != "" {
+		return s.saveAssignTemplate(c, name, plan, categories)
+	}
+
+	current, err := s.Client.BudgetCategoryReports(bID, monthStr)
+	if err != nil {
+		return err
+	}
+	changes, err := planChanges(plan, current, ExtractStrings(categories, func(c *pgo.Category) string { return c.Name }), iso)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Printf("Assignments in %s already match the plan; nothing to change.\n", month.Format(dates.MonthLayout))
+		return nil
+	}
+	rows, total, err := previewChanges(changes, iso)
+	if err != nil {
+		return fmt.Errorf("could not total planned changes: %w", err)
+	}
+	fmt.Printf("Planned assignments under budget %s in %s:\n", s.Session.ActiveBudget.Name, month.Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "ASSIGNED", "PLANNED", "CHANGE"}, rows))
+	fmt.Printf("  %s assigned in all\n", signed(total))
+
+	assignments := []pgo.BudgetCategoryAssignData{}
+	for _, change := range changes {
+		delta, err := change.delta()
+		if err != nil {
+			return err
+		}
+		assignments = append(assignments, pgo.BudgetCategoryAssignData{Amount: delta.Amount, ToCategory: change.category})
+	}
+	if c.globals.dryRun {
+		for _, assignment := range assignments {
+			c.dryRun("BudgetCategoryAssign", bID, monthStr, assignment)
+		}
+		return nil
+	}
+	confirmed, err := s.confirm(c, "assign", "Make these assignments? [y/N]: ", isYes)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("No assignments were made.")
+		return nil
+	}
+
+	err = s.applyAssignments(fmt.Sprintf("autoassign in budget %s", s.Session.ActiveBudget.Name), bID, monthStr, assignments)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Assigned %d categories for month %s\n", len(assignments), monthStr)
+	return nil
+}
+
+// assignPlan plans the money to assign to each category in a month by
+// the given strategy, by category name.
+func (s *State) assignPlan(c *handlerContext, strategy string, month time.Time, categories []*pgo.Category) (map[string]cc.Money, error) {
+	if strategy == "template" {
+		c.args.trackOptArgs(&c.cmd, "template")
+		name, _ := c.args.pfx()
+		if name == "" {
+			return nil, newCLIError(errKindUsage, "name the template to assign with --template")
+		}
+		templates := s.Config.AssignTemplates[s.Session.ActiveBudget.ID.String()]
+		template, ok := templates[name]
+		if !ok {
+			names := []string{}
+			for saved := range templates {
+				names = append(names, saved)
+			}
+			sort.Strings(names)
+			return nil, newCLIError(errKindNotFound, "no template named '%s' saved for budget %s%s", name, s.Session.ActiveBudget.Name, didYouMean(name, names))
+		}
+		plan := map[string]cc.Money{}
+		for _, category := range categories {
+			if amount, ok := template[category.ID.String()]; ok {
+				plan[category.Name] = amount
+			}
+		}
+		return plan, nil
+	}
+
+	count := 1
+	if strategy == "average" {
+		count = defaultAverageMonths
+		c.args.trackOptArgs(&c.cmd, "months")
+		if arg, _ := c.args.pfx(); arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 || n > maxReportMonths {
+				return nil, newCLIError(errKindUsage, "--months must be a whole number from 1 to %d", maxReportMonths)
+			}
+			count = n
+		}
+	}
+	earlier := []time.Time{}
+	for i := count; i > 0; i-- {
+		earlier = append(earlier, month.AddDate(0, -i, 0))
+	}
+	reports, err := s.fetchCategoryReports(earlier)
+	if err != nil {
+		return nil, err
+	}
+	return assignStrategies[strategy](reports, s.homeCurrency())
+}
+
+// saveAssignTemplate saves a plan as a template of the budget in view,
+// such that it may be assigned again with the template strategy.
+func (s *State) saveAssignTemplate(c *handlerContext, name string, plan map[string]cc.Money, categories []*pgo.Category) error {
+	template := config.AssignTemplate{}
+	for _, category := range categories {
+		if amount, ok := plan[category.Name]; ok {
+			template[category.ID.String()] = amount
+		}
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would save template '%s' of %d categories\n", name, len(template))
+		return nil
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	if s.Config.AssignTemplates == nil {
+		s.Config.AssignTemplates = map[string]map[string]config.AssignTemplate{}
+	}
+	if s.Config.AssignTemplates[bID] == nil {
+		s.Config.AssignTemplates[bID] = map[string]config.AssignTemplate{}
+	}
+	s.Config.AssignTemplates[bID][name] = template
+	err := s.Config.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save template: %w", err)
+	}
+	fmt.Printf("Saved template '%s' of %d categories; assign it with `budget autoassign template --template %s`\n", name, len(template), name)
+	return nil
+}
+
+func handleBudgetCover(s *State, c *handlerContext) error {
+	if s.Session.ActiveBudget.Name == "" {
+		return newCLIError(errKindPrecondition, "first view a budget to cover its overspending")
+	}
+	month, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
+	}
+	monthStr := month.Format(dates.DayLayout)
+	bID := s.Session.ActiveBudget.ID.String()
+	c.args.trackOptArgs(&c.cmd, "auto")
+	auto, _ := c.args.pfx()
+
+	reports, err := s.Client.BudgetCategoryReports(bID, monthStr)
+	if err != nil {
+		return err
+	}
+	overspent, sources := findOverspending(reports, s.homeCurrency())
+	if len(overspent) == 0 {
+		fmt.Printf("No category is overspent in %s.\n", month.Format(dates.MonthLayout))
+		return nil
+	}
+	paintOverspent := s.paint(red)
+	rows := [][]string{}
+	for _, short := range overspent {
+		rows = append(rows, []string{short.category, paintOverspent(short.balance.String())})
+	}
+	fmt.Printf("Overspent categories under budget %s in %s:\n", s.Session.ActiveBudget.Name, month.Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "BALANCE"}, rows))
+	if len(sources) == 0 {
+		fmt.Println("No category has money to spare to cover it; assign more with `category assign`.")
+		return nil
+	}
+
+	var moves []coverMove
+	var uncovered []categoryBalance
+	if auto == "SET" || c.globals.yes || c.globals.dryRun {
+		if moves, uncovered, err = planCover(overspent, sources); err != nil {
+			return fmt.Errorf("could not plan cover: %w", err)
+		}
+		rows := [][]string{}
+		for _, move := range moves {
+			rows = append(rows, []string{move.from, move.to, move.amount.String()})
+		}
+		fmt.Println("Moving money to cover it:")
+		fmt.Print(makeTable([]string{"FROM", "TO", "AMOUNT"}, rows))
+	} else {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return newCLIError(errKindPrecondition, "refusing to choose how to cover overspending; input is not a terminal (use --auto)")
+		}
+		if moves, uncovered, err = s.chooseCover(overspent, sources); err != nil {
+			return err
+		}
+	}
+	if len(moves) == 0 {
+		fmt.Println("No money was moved.")
+		return nil
+	}
+
+	assignments := []pgo.BudgetCategoryAssignData{}
+	for _, move := range moves {
+		assignments = append(assignments, pgo.BudgetCategoryAssignData{
+			Amount:       move.amount.Amount,
+			ToCategory:   move.to,
+			FromCategory: move.from,
+		})
+	}
+	if c.globals.dryRun {
+		for _, assignment := range assignments {
+			c.dryRun("BudgetCategoryAssign", bID, monthStr, assignment)
+		}
+		return nil
+	}
+	err = s.applyAssignments(fmt.Sprintf("cover overspending in budget %s", s.Session.ActiveBudget.Name), bID, monthStr, assignments)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Made %d moves to cover overspending in %s\n", len(assignments), month.Format(dates.MonthLayout))
+	for _, short := range uncovered {
+		fmt.Printf("  %s is still overspent by %s\n", short.category, short.balance)
+	}
+	return nil
+}
+
+// chooseCover asks which category to cover each overspent category from
+// in turn, suggesting the one with the most to spare, until each is
+// covered, skipped, or there is no money left to spare. It returns the
+// moves chosen along with the overspending left uncovered.
+func (s *State) chooseCover(overspent, sources []categoryBalance) ([]coverMove, []categoryBalance, error) {
+	sources = slices.Clone(sources)
+	moves := []coverMove{}
+	uncovered := []categoryBalance{}
+	for _, short := range overspent {
+		shortfall, err := short.balance.Neg()
+		if err != nil {
+			return nil, nil, err
+		}
+		for shortfall.IsPositive() {
+			most, ok := mostToSpare(sources)
+			if !ok {
+				break
+			}
+			available := []int{}
+			spare := []string{}
+			for i, source := range sources {
+				if source.balance.IsPositive() {
+					available = append(available, i)
+					spare = append(spare, fmt.Sprintf("%s (%s)", source.category, source.balance))
+				}
+			}
+			fmt.Printf("%s is overspent by %s. Money to spare: %s\n", short.category, shortfall, strings.Join(spare, ", "))
+			answer, err := s.readLine(fmt.Sprintf("Cover from which category? [%s, or skip]: ", sources[most].category))
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not read choice: %w", err)
+			}
+			if strings.EqualFold(answer, "skip") {
+				break
+			}
+			choice := most
+			if answer != "" {
+				nameOf := func(i int) string { return sources[i].category }
+				if choice, err = resolveResource("categories", answer, available, nameOf, nameOf); err != nil {
+					fmt.Println("  " + err.Error())
+					continue
+				}
+			}
+			var move coverMove
+			if move, shortfall, err = takeFrom(&sources[choice], short.category, shortfall); err != nil {
+				return nil, nil, err
+			}
+			moves = append(moves, move)
+		}
+		if shortfall.IsPositive() {
+			uncovered = append(uncovered, categoryBalance{category: short.category, balance: shortfall})
+		}
+	}
+	return moves, uncovered, nil
+}
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
-	"time"
 
 	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	"github.com/YouWantToPinch/pincher-cli/internal/goals"
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
 )
 
@@ -19,7 +21,7 @@ func handlerCategory(s *State, c *handlerContext) error {
 		case "update":
 			return handleCategoryUpdate(s, c)
 		case "delete":
-			return handleCategoryDelete(s, c)
+			return middlewareConfirm(describeCategoryDelete)(handleCategoryDelete)(s, c)
 		case "assign":
 			return handleCategoryAssign(s, c)
 		case "reports":
@@ -40,108 +42,136 @@ func handleCategoryAdd(s *State, c *handlerContext) error {
 
 	c.args.trackOptArgs(&c.cmd, "group")
 	groupName, _ := c.args.pfx()
+	if groupName != "" {
+		var err error
+		if groupName, err = s.resolveGroupName(groupName); err != nil {
+			return err
+		}
+	}
 
-	err := s.Client.BudgetCategoryCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetCategoryCreateData{
+	payload := pgo.BudgetCategoryCreateData{
 		MetaData: pgo.MetaData{
 			Name:  name,
 			Notes: notes,
 		},
 		GroupName: groupName,
-	})
+	}
+	if c.dryRun("BudgetCategoryCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	cID, err := createFound(
+		func() ([]*pgo.Category, error) { return s.Client.BudgetCategories(bID, "") },
+		func(c *pgo.Category) string { return c.ID.String() },
+		func() error { return s.Client.BudgetCategoryCreate(bID, payload) },
+	)
 	if err != nil {
 		return err
 	}
+	if cID != "" {
+		s.Session.UndoStack.push(
+			fmt.Sprintf("add category '%s' to budget %s", name, s.Session.ActiveBudget.Name),
+			func(s *State) error { return s.Client.BudgetCategoryDelete(bID, cID) },
+		)
+	}
 	fmt.Println("Category " + name + " successfully created as user: " + s.Session.ActiveUser.Username)
 	fmt.Println("See it with: `category list`")
 	return nil
 }
 
 func handleCategoryAssign(s *State, c *handlerContext) error {
+	if _, fundingGoals := c.cmd.opts["fund-goals"]; fundingGoals {
+		if len(c.cmd.args) > 1 {
+			return newCLIError(errKindUsage, "give either a category and amount to assign, or --fund-goals, but not both")
+		}
+		return fundGoals(s, c)
+	}
 	toCategory, _ := c.args.pfx()
+	toCategory, err := s.resolveCategoryName(toCategory)
+	if err != nil {
+		return err
+	}
 
 	amount, _ := c.args.pfx()
-	parsedAmount, err := cc.Parse(amount, s.Config.CurrencyISOCode)
+	assignment, err := cc.EvaluateMoney(amount, s.homeCurrency())
 	if err != nil {
 		return err
 	}
 
-	monthTime := time.Now()
-	c.args.trackOptArgs(&c.cmd, "month")
-	month, _ := c.args.pfx()
-	if month != "" {
-		monthTime, err = time.Parse("2006-01", month)
-		if err != nil {
-			return fmt.Errorf("bad month format; use YYYY-MM")
-		}
+	monthTime, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
 	}
-	monthStr := monthTime.Format("2006-01-02")
+	monthStr := monthTime.Format(dates.DayLayout)
 
 	c.args.trackOptArgs(&c.cmd, "from")
 	fromCategory, _ := c.args.pfx()
+	if fromCategory != "" {
+		if fromCategory, err = s.resolveCategoryName(fromCategory); err != nil {
+			return err
+		}
+	}
 
-	err = s.Client.BudgetCategoryAssign(s.Session.ActiveBudget.ID.String(), monthStr, pgo.BudgetCategoryAssignData{
-		Amount:       parsedAmount,
+	payload := pgo.BudgetCategoryAssignData{
+		Amount:       assignment.Amount,
 		ToCategory:   toCategory,
 		FromCategory: fromCategory,
-	})
+	}
+	if c.dryRun("BudgetCategoryAssign", s.Session.ActiveBudget.ID.String(), monthStr, payload) {
+		return nil
+	}
+	err = s.Client.BudgetCategoryAssign(s.Session.ActiveBudget.ID.String(), monthStr, payload)
 	if err != nil {
 		return err
 	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("assign %s to category '%s' in budget %s", assignment, toCategory, s.Session.ActiveBudget.Name),
+		undoCategoryAssign(s.Session.ActiveBudget.ID.String(), monthStr, pgo.BudgetCategoryAssignData{
+			Amount:       assignment.Amount,
+			ToCategory:   toCategory,
+			FromCategory: fromCategory,
+		}),
+	)
 	if fromCategory == "" {
-		fmt.Printf("Assigned %s to category %s for month %s\n", amount, toCategory, monthStr)
+		fmt.Printf("Assigned %s to category %s for month %s\n", assignment, toCategory, monthStr)
 	} else {
-		fmt.Printf("Assigned %s to category %s from %s in month %s\n", amount, toCategory, fromCategory, monthStr)
+		fmt.Printf("Assigned %s to category %s from %s in month %s\n", assignment, toCategory, fromCategory, monthStr)
 	}
 
 	return nil
 }
 
 func handleCategoryReports(s *State, c *handlerContext) error {
-	var err error
-
-	monthTime := time.Now()
-	c.args.trackOptArgs(&c.cmd, "month")
-	month, _ := c.args.pfx()
-	if month != "" {
-		monthTime, err = time.Parse("2006-01", month)
-		if err != nil {
-			return fmt.Errorf("bad month format; use YYYY-MM")
-		}
+	months, isRange, err := s.monthRange(c)
+	if err != nil {
+		return err
+	}
+	if isRange {
+		return s.printReportMatrix(c, months)
+	}
+	monthTime, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
 	}
 
-	reports, err := s.Client.BudgetCategoryReports(s.Session.ActiveBudget.ID.String(), monthTime.Format("2006-01-02"))
+	reports, err := s.Client.BudgetCategoryReports(s.Session.ActiveBudget.ID.String(), monthTime.Format(dates.DayLayout))
 	if err != nil {
 		return err
 	}
-	sort.Slice(reports, func(i, j int) bool {
-		return reports[i].Name < reports[j].Name
-	})
 	if len(reports) == 0 {
 		fmt.Println("Nothing to report.")
 		return nil
 	}
-	fmt.Printf("Categories under budget %s: \n", s.Session.ActiveBudget.Name)
-	maxLenMonth := MaxOfStrings(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return r.MonthID.Format("2006-01") })...)
-	maxLenName := MaxOfStrings(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return r.Name })...)
-	maxLenAssigned := MaxOfStrings(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return cc.Format(r.Assigned, s.Config.CurrencyISOCode, true) })...)
-	maxLenActivity := MaxOfStrings(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return cc.Format(r.Activity, s.Config.CurrencyISOCode, true) })...)
-	maxLenBalance := MaxOfStrings(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return cc.Format(r.Balance, s.Config.CurrencyISOCode, true) })...)
-	fmt.Printf("  %-*s | %-*s | %-*s | %-*s | %s\n", maxLenMonth, "MONTH", maxLenName, "NAME", maxLenAssigned, "ASSIGNED", maxLenActivity, "ACTIVITY", "BALANCE")
-	fmt.Printf("  %s-+-%s-+-%s-+-%s-+-%s\n", nDashes(maxLenMonth), nDashes(maxLenName), nDashes(maxLenAssigned), nDashes(maxLenActivity), nDashes(maxLenBalance))
-	for _, report := range reports {
-		fmt.Printf("  %-*s | %-*s | %-*s | %-*s | %s\n",
-			maxLenMonth,
-			report.MonthID.Format("2006-01"),
-			maxLenName,
-			report.Name,
-			maxLenAssigned,
-			cc.Format(report.Assigned, s.Config.CurrencyISOCode, true),
-			maxLenActivity,
-			cc.Format(report.Activity, s.Config.CurrencyISOCode, true),
-			cc.Format(report.Balance, s.Config.CurrencyISOCode, true),
-		)
+	sections, err := s.reportSections(ExtractStrings(reports, func(r *pgo.CategoryReport) string { return r.Name }))
+	if err != nil {
+		return err
 	}
-
+	rows, err := categoryTree(reports, sections, s.homeCurrency(), s.paint(red))
+	if err != nil {
+		return fmt.Errorf("could not total report: %w", err)
+	}
+	fmt.Printf("Categories under budget %s in %s: \n", s.Session.ActiveBudget.Name, monthTime.Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "ASSIGNED", "ACTIVITY", "BALANCE"}, rows))
 	return nil
 }
 
@@ -150,7 +180,11 @@ func handleCategoryList(s *State, c *handlerContext) error {
 	c.args.trackOptArgs(&c.cmd, "group")
 	groupName, _ := c.args.pfx()
 	if groupName != "" {
-		groupQuery = "?group_name=" + groupName
+		groupName, err := s.resolveGroupName(groupName)
+		if err != nil {
+			return err
+		}
+		groupQuery = "?group_name=" + url.QueryEscape(groupName)
 	}
 
 	categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), groupQuery)
@@ -184,13 +218,18 @@ func handleCategoryUpdate(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	category, err := findCategoryByName(categoryName, categories)
+	category, err := resolveCategory(categoryName, categories)
 	if err != nil {
 		return err
 	}
 
 	c.args.trackOptArgs(&c.cmd, "group")
 	groupName, _ := c.args.pfx()
+	if groupName != "" {
+		if groupName, err = s.resolveGroupName(groupName); err != nil {
+			return err
+		}
+	}
 
 	c.args.trackOptArgs(&c.cmd, "name")
 	payloadName, err := c.args.pfx()
@@ -203,20 +242,90 @@ func handleCategoryUpdate(s *State, c *handlerContext) error {
 		payloadNotes = category.Notes
 	}
 
-	err = s.Client.BudgetCategoryUpdate(s.Session.ActiveBudget.ID.String(), category.ID.String(), pgo.BudgetCategoryUpdateData{
+	priorGroupName, err := s.groupNameOf(category)
+	if err != nil {
+		return err
+	}
+
+	payload := pgo.BudgetCategoryUpdateData{
 		MetaData: pgo.MetaData{
 			Name:  payloadName,
 			Notes: payloadNotes,
 		},
 		GroupName: groupName,
-	})
+	}
+	if c.dryRun("BudgetCategoryUpdate", s.Session.ActiveBudget.ID.String(), category.ID.String(), payload) {
+		return nil
+	}
+	err = s.Client.BudgetCategoryUpdate(s.Session.ActiveBudget.ID.String(), category.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	bID, prior := s.Session.ActiveBudget.ID.String(), *category
+	s.Session.UndoStack.push(
+		fmt.Sprintf("update category '%s' in budget %s", category.Name, s.Session.ActiveBudget.Name),
+		func(s *State) error {
+			return s.Client.BudgetCategoryUpdate(bID, prior.ID.String(), pgo.BudgetCategoryUpdateData{
+				MetaData: pgo.MetaData{
+					Name:  prior.Name,
+					Notes: prior.Notes,
+				},
+				GroupName: priorGroupName,
+			})
+		},
+	)
 	fmt.Println("Category updated with new information")
 	return nil
 }
 
+// groupNameOf returns the name of the group that a category
+// belongs to, or an empty string if it belongs to none.
+func (s *State) groupNameOf(category *pgo.Category) (string, error) {
+	if category.GroupID == nil {
+		return "", nil
+	}
+	groups, err := s.GetGroups(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return "", err
+	}
+	for _, group := range groups {
+		if group.ID == *category.GroupID {
+			return group.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func describeCategoryDelete(s *State, c *handlerContext) (*destruction, error) {
+	name, _ := c.args.peek()
+
+	categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	category, err := resolveCategory(name, categories)
+	if err != nil {
+		return nil, err
+	}
+	txnCount, err := s.countTxns(s.Session.ActiveBudget.ID.String(), url.Values{"category_name": {category.Name}})
+	if err != nil {
+		return nil, err
+	}
+	effects := []string{fmt.Sprintf("%d transaction(s) are categorized under it", txnCount)}
+	book, err := goals.ReadFromFile()
+	if err != nil {
+		return nil, fmt.Errorf("could not read goals: %w", err)
+	}
+	if goal, ok := book.Get(category.ID.String()); ok {
+		effects = append(effects, fmt.Sprintf("its goal of %s will be deleted along with it", goal))
+	}
+	return &destruction{
+		kind:    "category",
+		name:    category.Name,
+		effects: effects,
+	}, nil
+}
+
 func handleCategoryDelete(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 
@@ -224,15 +333,82 @@ func handleCategoryDelete(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	category, err := findCategoryByName(name, categories)
+	category, err := resolveCategory(name, categories)
 	if err != nil {
 		return err
 	}
 
+	if c.dryRun("BudgetCategoryDelete", s.Session.ActiveBudget.ID.String(), category.ID.String()) {
+		return nil
+	}
 	err = s.Client.BudgetCategoryDelete(s.Session.ActiveBudget.ID.String(), category.ID.String())
 	if err != nil {
 		return err
 	}
+	book, err := goals.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read goals: %w", err)
+	}
+	if book.Remove(category.ID.String()) {
+		err = book.WriteToFile()
+		if err != nil {
+			return fmt.Errorf("could not forget category goal: %w", err)
+		}
+	}
 	fmt.Println("Category deleted.")
 	return nil
 }
+
+// undoCategoryAssign returns a function which reverses an assignment:
+// money moved between categories is moved back, and money assigned
+// from nowhere in particular is unassigned.
+func undoCategoryAssign(bID, month string, assigned pgo.BudgetCategoryAssignData) func(*State) error {
+	return func(s *State) error {
+		reverse := pgo.BudgetCategoryAssignData{
+			Amount:     -assigned.Amount,
+			ToCategory: assigned.ToCategory,
+		}
+		if assigned.FromCategory != "" {
+			reverse = pgo.BudgetCategoryAssignData{
+				Amount:       assigned.Amount,
+				ToCategory:   assigned.FromCategory,
+				FromCategory: assigned.ToCategory,
+			}
+		}
+		return s.Client.BudgetCategoryAssign(bID, month, reverse)
+	}
+}
+
+// applyAssignments makes several assignments in a month, stopping at
+// the first to fail. Those made are recorded as a single entry, such
+// that they are undone together, even when the rest could not be made.
+func (s *State) applyAssignments(description, bID, month string, assignments []pgo.BudgetCategoryAssignData) error {
+	made := []pgo.BudgetCategoryAssignData{}
+	pushUndo := func() {
+		s.Session.UndoStack.push(fmt.Sprintf("%s (%d assignments)", description, len(made)), func(s *State) error {
+			for _, assignment := range made {
+				if err := undoCategoryAssign(bID, month, assignment)(s); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	for _, assignment := range assignments {
+		if err := s.Client.BudgetCategoryAssign(bID, month, assignment); err != nil {
+			if assignment.FromCategory != "" {
+				err = fmt.Errorf("could not move money from %s to %s: %w", assignment.FromCategory, assignment.ToCategory, err)
+			} else {
+				err = fmt.Errorf("could not assign to category %s: %w", assignment.ToCategory, err)
+			}
+			if len(made) > 0 {
+				pushUndo()
+				err = fmt.Errorf("%w; assignments already made may be undone with `undo`", err)
+			}
+			return err
+		}
+		made = append(made, assignment)
+	}
+	pushUndo()
+	return nil
+}
@@ -2,8 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/YouWantToPinch/pincher-cli/internal/config"
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
 	ui "github.com/bntrtm/gostructui"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,6 +19,8 @@ func handlerConfig(s *State, c *handlerContext) error {
 			return handleConfigEdit(s, c)
 		case "load":
 			return handleConfigLoad(s, c)
+		case "set":
+			return handleConfigSet(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -44,6 +50,10 @@ func handleConfigEdit(s *State, c *handlerContext) error {
 			if err != nil {
 				return err
 			}
+			err = validateConfigSettings(&newConfig)
+			if err != nil {
+				return err
+			}
 			s.Config.ConfigSettings = newConfig
 			err := s.Config.WriteToFile()
 			if err != nil {
@@ -71,3 +81,88 @@ func handleConfigLoad(s *State, c *handlerContext) error {
 	fmt.Println("Loaded configuration settings.")
 	return nil
 }
+
+// configSetters apply a value given as text to a single setting,
+// keyed by the name by which it may be referred to from the CLI.
+var configSetters = map[string]func(settings *config.ConfigSettings, value string) error{
+	"currency": func(settings *config.ConfigSettings, value string) error {
+		settings.CurrencyISOCode = value
+		return nil
+	},
+	"stay-logged-in": func(settings *config.ConfigSettings, value string) error {
+		stay, err := strconv.ParseBool(value)
+		if err != nil {
+			return newCLIError(errKindUsage, "stay-logged-in must be true or false")
+		}
+		settings.StayLoggedIn = stay
+		return nil
+	},
+	"date-format": func(settings *config.ConfigSettings, value string) error {
+		settings.DateFormat = value
+		return nil
+	},
+	"prompt-ready": func(settings *config.ConfigSettings, value string) error {
+		show, err := strconv.ParseBool(value)
+		if err != nil {
+			return newCLIError(errKindUsage, "prompt-ready must be true or false")
+		}
+		settings.PromptReadyToAssign = show
+		return nil
+	},
+	"vim-keys": func(settings *config.ConfigSettings, value string) error {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return newCLIError(errKindUsage, "vim-keys must be true or false")
+		}
+		settings.VimKeysEnabled = enabled
+		return nil
+	},
+}
+
+func handleConfigSet(s *State, c *handlerContext) error {
+	setting, _ := c.args.pfx()
+	value, _ := c.args.pfx()
+
+	set, ok := configSetters[setting]
+	if !ok {
+		names := make([]string, 0, len(configSetters))
+		for name := range configSetters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return newCLIError(errKindUsage, "unknown setting '%s'%s", setting, didYouMean(setting, names))
+	}
+
+	newConfig := s.Config.ConfigSettings
+	err := set(&newConfig, value)
+	if err != nil {
+		return err
+	}
+	err = validateConfigSettings(&newConfig)
+	if err != nil {
+		return err
+	}
+	s.Config.ConfigSettings = newConfig
+	err = s.Config.WriteToFile()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Set %s to: %s\n", setting, value)
+	return nil
+}
+
+// validateConfigSettings rejects settings that the CLI could not
+// work with, normalizing those that it can.
+func validateConfigSettings(settings *config.ConfigSettings) error {
+	currency, err := cc.Lookup(settings.CurrencyISOCode)
+	if err != nil {
+		return newCLIError(errKindUsage, "%v", err)
+	}
+	settings.CurrencyISOCode = currency.ISOCode
+	if settings.DateFormat != "" {
+		if _, err := dates.LayoutFromFormat(settings.DateFormat); err != nil {
+			return newCLIError(errKindUsage, "%v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	"github.com/YouWantToPinch/pincher-cli/internal/goals"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func handlerGoal(s *State, c *handlerContext) error {
+	if val, ok := c.ctxValues["action"]; ok {
+		switch val {
+		case "set":
+			return handleGoalSet(s, c)
+		case "delete":
+			return handleGoalDelete(s, c)
+		case "status":
+			return handleGoalStatus(s, c)
+		default:
+			return fmt.Errorf("action not implemented")
+		}
+	} else {
+		return fmt.Errorf("action was not saved to context")
+	}
+}
+
+func handleGoalSet(s *State, c *handlerContext) error {
+	name, _ := c.args.pfx()
+	kind, _ := c.args.pfx()
+	amountArg, _ := c.args.pfx()
+
+	bID := s.Session.ActiveBudget.ID.String()
+	categories, err := s.GetCategories(bID, "")
+	if err != nil {
+		return err
+	}
+	category, err := resolveCategory(name, categories)
+	if err != nil {
+		return err
+	}
+	kind = strings.ToLower(kind)
+	if !slices.Contains(goals.Kinds, kind) {
+		return newCLIError(errKindUsage, "unknown kind of goal '%s'%s", kind, didYouMean(kind, goals.Kinds))
+	}
+	amount, err := cc.EvaluateMoney(amountArg, s.homeCurrency())
+	if err != nil {
+		return err
+	}
+	var by time.Time
+	c.args.trackOptArgs(&c.cmd, "by")
+	if byArg, _ := c.args.pfx(); byArg != "" {
+		p, err := s.dateParser()
+		if err != nil {
+			return err
+		}
+		if by, err = p.Month(byArg); err != nil {
+			return newCLIError(errKindUsage, "%v", err)
+		}
+	}
+	goal, err := goals.NewGoal(bID, category.ID.String(), kind, amount, by)
+	if err != nil {
+		return newCLIError(errKindUsage, "%v", err)
+	}
+
+	book, err := goals.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read goals: %w", err)
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would set goal for category %s: %s\n", category.Name, goal)
+		return nil
+	}
+	prior, replaced := book.Get(goal.CategoryID)
+	book.Set(goal)
+	err = book.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save goals: %w", err)
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("set goal for category '%s' in budget %s", category.Name, s.Session.ActiveBudget.Name),
+		undoGoalChange(goal.CategoryID, prior, replaced),
+	)
+	if replaced {
+		fmt.Printf("Replaced goal for category %s: %s\n", category.Name, goal)
+	} else {
+		fmt.Printf("Set goal for category %s: %s\n", category.Name, goal)
+	}
+	return nil
+}
+
+func handleGoalDelete(s *State, c *handlerContext) error {
+	name, _ := c.args.pfx()
+
+	categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return err
+	}
+	category, err := resolveCategory(name, categories)
+	if err != nil {
+		return err
+	}
+
+	book, err := goals.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read goals: %w", err)
+	}
+	prior, found := book.Get(category.ID.String())
+	if !found {
+		return newCLIError(errKindNotFound, "no goal set for category %s", category.Name)
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would delete goal for category %s\n", category.Name)
+		return nil
+	}
+	book.Remove(prior.CategoryID)
+	err = book.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save goals: %w", err)
+	}
+	s.Session.UndoStack.push(
+		fmt.Sprintf("delete goal for category '%s' in budget %s", category.Name, s.Session.ActiveBudget.Name),
+		undoGoalChange(prior.CategoryID, prior, true),
+	)
+	fmt.Printf("Deleted goal for category %s\n", category.Name)
+	return nil
+}
+
+// undoGoalChange returns a function which puts back the goal a category
+// had before it was changed, or removes its goal if it had none.
+func undoGoalChange(categoryID string, prior goals.Goal, hadPrior bool) func(*State) error {
+	return func(s *State) error {
+		book, err := goals.ReadFromFile()
+		if err != nil {
+			return fmt.Errorf("could not read goals: %w", err)
+		}
+		if hadPrior {
+			book.Set(prior)
+		} else {
+			book.Remove(categoryID)
+		}
+		return book.WriteToFile()
+	}
+}
+
+func handleGoalStatus(s *State, c *handlerContext) error {
+	month, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
+	}
+	statuses, err := s.goalStatuses(month)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Printf("No goals set for categories under budget %s.\n", s.Session.ActiveBudget.Name)
+		fmt.Println("Set one with: `goal set <category_name> <monthly|target|cap> <amount>`")
+		return nil
+	}
+
+	paint, paintOver := s.paint(green), s.paint(red)
+	rows := [][]string{}
+	underfunded := []string{}
+	needed := cc.NewMoney(0, s.homeCurrency())
+	for _, status := range statuses {
+		p := status.progress
+		drawn := progressBar(p.Fraction, 20)
+		if p.Over() {
+			drawn = paintOver(drawn)
+		} else {
+			drawn = paint(drawn)
+		}
+		rows = append(rows, []string{
+			status.category,
+			p.Goal.String(),
+			p.Needed.String(),
+			fmt.Sprintf("%s %3.0f%%", drawn, p.Fraction*100),
+		})
+		if p.Underfunded() {
+			underfunded = append(underfunded, status.category)
+			if needed, err = needed.Add(p.Needed); err != nil {
+				return fmt.Errorf("could not total money needed: %w", err)
+			}
+		}
+	}
+	fmt.Printf("Goals under budget %s in %s:\n", s.Session.ActiveBudget.Name, month.Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "GOAL", "NEEDED", "PROGRESS"}, rows))
+	if len(underfunded) == 0 {
+		fmt.Println("  Every goal is funded.")
+		return nil
+	}
+	fmt.Printf("  Underfunded: %s\n", strings.Join(underfunded, ", "))
+	fmt.Printf("  %s more needed in all; assign it with `category assign --fund-goals`\n", needed)
+	return nil
+}
+
+// goalStatus is the progress made toward a goal,
+// along with the name of its category.
+type goalStatus struct {
+	category string
+	progress goals.Progress
+}
+
+// goalStatuses works out the progress made toward each goal set for the
+// categories of the budget in view in a month, in order of category name.
+func (s *State) goalStatuses(month time.Time) ([]goalStatus, error) {
+	bID := s.Session.ActiveBudget.ID.String()
+	book, err := goals.ReadFromFile()
+	if err != nil {
+		return nil, fmt.Errorf("could not read goals: %w", err)
+	}
+	set := book.ForBudget(bID)
+	if len(set) == 0 {
+		return nil, nil
+	}
+	categories, err := s.GetCategories(bID, "")
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.Client.BudgetCategoryReports(bID, month.Format(dates.DayLayout))
+	if err != nil {
+		return nil, err
+	}
+	return progressOfGoals(set, categories, reports, month, s.homeCurrency())
+}
+
+// progressOfGoals works out the progress made toward each goal in a month,
+// given the reports of the categories in that month, in order of category
+// name. Goals left behind by categories which no longer exist are skipped.
+func progressOfGoals(set []goals.Goal, categories []*pgo.Category, reports []*pgo.CategoryReport, month time.Time, ISOCode string) ([]goalStatus, error) {
+	names := map[string]string{}
+	for _, category := range categories {
+		names[category.ID.String()] = category.Name
+	}
+	byName := map[string]*pgo.CategoryReport{}
+	for _, report := range reports {
+		byName[report.Name] = report
+	}
+
+	statuses := []goalStatus{}
+	for _, goal := range set {
+		name, ok := names[goal.CategoryID]
+		if !ok {
+			continue
+		}
+		assigned, activity, balance := cc.NewMoney(0, ISOCode), cc.NewMoney(0, ISOCode), cc.NewMoney(0, ISOCode)
+		if report, ok := byName[name]; ok {
+			assigned = cc.NewMoney(report.Assigned, ISOCode)
+			activity = cc.NewMoney(report.Activity, ISOCode)
+			balance = cc.NewMoney(report.Balance, ISOCode)
+		}
+		progress, err := goal.Progress(month, assigned, activity, balance)
+		if err != nil {
+			return nil, fmt.Errorf("could not work out goal of category %s: %w", name, err)
+		}
+		statuses = append(statuses, goalStatus{category: name, progress: progress})
+	}
+	slices.SortFunc(statuses, func(a, b goalStatus) int { return cmp.Compare(a.category, b.category) })
+	return statuses, nil
+}
+
+// fundingOfGoals shares out the money available among the goals which need
+// it, giving each all that it needs in turn until the money runs out. It
+// returns the amount to assign to each category funded, in the order
+// given, along with the money still needed once it has run out.
+func fundingOfGoals(statuses []goalStatus, available cc.Money) ([]goalStatus, []cc.Money, cc.Money, error) {
+	funded := []goalStatus{}
+	amounts := []cc.Money{}
+	unmet := cc.NewMoney(0, available.ISOCode)
+	if available.IsNegative() {
+		// overassigned, there is nothing to share out
+		available = unmet
+	}
+	for _, status := range statuses {
+		if !status.progress.Underfunded() {
+			continue
+		}
+		amount := status.progress.Needed
+		isMore, err := amount.Cmp(available)
+		if err != nil {
+			return nil, nil, cc.Money{}, err
+		}
+		if isMore > 0 {
+			amount = available
+		}
+		shortBy, err := status.progress.Needed.Sub(amount)
+		if err != nil {
+			return nil, nil, cc.Money{}, err
+		}
+		if unmet, err = unmet.Add(shortBy); err != nil {
+			return nil, nil, cc.Money{}, err
+		}
+		if !amount.IsPositive() {
+			continue
+		}
+		if available, err = available.Sub(amount); err != nil {
+			return nil, nil, cc.Money{}, err
+		}
+		funded = append(funded, status)
+		amounts = append(amounts, amount)
+	}
+	return funded, amounts, unmet, nil
+}
+
+// fundGoals assigns the money ready to assign in a month to the
+// categories whose goals need it, as far as it goes.
+func fundGoals(s *State, c *handlerContext) error {
+	c.args.trackOptArgs(&c.cmd, "from")
+	if from, _ := c.args.pfx(); from != "" {
+		return newCLIError(errKindUsage, "--from cannot be used along with --fund-goals, which assigns the money ready to assign")
+	}
+	month, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
+	}
+	monthStr := month.Format(dates.DayLayout)
+	bID := s.Session.ActiveBudget.ID.String()
+
+	statuses, err := s.goalStatuses(month)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return newCLIError(errKindPrecondition, "no goals set for categories under budget %s; set one with `goal set`", s.Session.ActiveBudget.Name)
+	}
+	report, err := s.Client.BudgetReport(bID, monthStr)
+	if err != nil {
+		return err
+	}
+	readiness, err := s.readyToAssign(month, report)
+	if err != nil {
+		return fmt.Errorf("could not work out money ready to assign: %w", err)
+	}
+	funded, amounts, unmet, err := fundingOfGoals(statuses, readiness.ready)
+	if err != nil {
+		return fmt.Errorf("could not share out money among goals: %w", err)
+	}
+	if len(funded) == 0 {
+		if unmet.IsPositive() {
+			return newCLIError(errKindPrecondition, "no money is ready to assign in %s to fund goals, which need %s", month.Format(dates.MonthLayout), unmet)
+		}
+		fmt.Println("Every goal is funded; nothing to assign.")
+		return nil
+	}
+
+	assignments := []pgo.BudgetCategoryAssignData{}
+	for i, status := range funded {
+		assignments = append(assignments, pgo.BudgetCategoryAssignData{
+			Amount:     amounts[i].Amount,
+			ToCategory: status.category,
+		})
+	}
+	if c.globals.dryRun {
+		for _, assignment := range assignments {
+			c.dryRun("BudgetCategoryAssign", bID, monthStr, assignment)
+		}
+		return nil
+	}
+
+	rows := [][]string{}
+	total := cc.NewMoney(0, readiness.ready.ISOCode)
+	for i, status := range funded {
+		rows = append(rows, []string{status.category, amounts[i].String()})
+		if total, err = total.Add(amounts[i]); err != nil {
+			return fmt.Errorf("could not total assignments: %w", err)
+		}
+	}
+	err = s.applyAssignments(fmt.Sprintf("fund goals in budget %s", s.Session.ActiveBudget.Name), bID, monthStr, assignments)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Funded goals under budget %s in %s:\n", s.Session.ActiveBudget.Name, month.Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "ASSIGNED"}, rows))
+	left, err := readiness.ready.Sub(total)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Assigned %s in all; %s left ready to assign\n", total, left)
+	if unmet.IsPositive() {
+		fmt.Printf("  %s more is needed to fund every goal; see `goal status`\n", unmet)
+	}
+	return nil
+}
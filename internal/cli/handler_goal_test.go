@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/goals"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestProgressOfGoals(t *testing.T) {
+	categories := []*pgo.Category{}
+	for i, name := range []string{"Rent", "Dining", "Holiday"} {
+		category := &pgo.Category{MetaData: pgo.MetaData{Name: name}}
+		category.ID[15] = byte(i + 1)
+		categories = append(categories, category)
+	}
+	id := func(i int) string { return categories[i].ID.String() }
+	set := []goals.Goal{
+		{CategoryID: id(0), Kind: goals.KindMonthly, Amount: cc.NewMoney(120000, "USD")},
+		{CategoryID: id(1), Kind: goals.KindCap, Amount: cc.NewMoney(20000, "USD")},
+		{CategoryID: id(2), Kind: goals.KindTarget, Amount: cc.NewMoney(60000, "USD"), By: "2026-12"},
+		// left behind by a deleted category
+		{CategoryID: "gone", Kind: goals.KindMonthly, Amount: cc.NewMoney(100, "USD")},
+	}
+	reports := []*pgo.CategoryReport{
+		{Name: "Rent", Assigned: 120000, Activity: -120000, Balance: 0},
+		{Name: "Dining", Assigned: 20000, Activity: -25000, Balance: -5000},
+	}
+
+	statuses, err := progressOfGoals(set, categories, reports, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, status := range statuses {
+		names = append(names, status.category)
+	}
+	if !slices.Equal(names, []string{"Dining", "Holiday", "Rent"}) {
+		t.Fatalf("expected goals of Dining, Holiday and Rent, got %q", names)
+	}
+	if !statuses[0].progress.Over() {
+		t.Error("expected Dining to be over its cap")
+	}
+	if !statuses[1].progress.Needed.Equal(cc.NewMoney(20000, "USD")) {
+		t.Errorf("expected Holiday to need $200.00, having no report, got %v", statuses[1].progress.Needed)
+	}
+	if statuses[2].progress.Underfunded() {
+		t.Error("expected Rent to be funded")
+	}
+}
+
+func TestFundingOfGoals(t *testing.T) {
+	status := func(category string, needed int64) goalStatus {
+		return goalStatus{
+			category: category,
+			progress: goals.Progress{Needed: cc.NewMoney(needed, "USD")},
+		}
+	}
+	statuses := []goalStatus{status("Dining", 0), status("Holiday", 20000), status("Rent", 50000), status("Savings", 10000)}
+
+	tests := []struct {
+		name               string
+		available          int64
+		expectedCategories []string
+		expectedAmounts    []int64
+		expectedUnmet      int64
+	}{
+		{
+			name:               "enough for every goal",
+			available:          100000,
+			expectedCategories: []string{"Holiday", "Rent", "Savings"},
+			expectedAmounts:    []int64{20000, 50000, 10000},
+		},
+		{
+			name:               "runs out partway",
+			available:          30000,
+			expectedCategories: []string{"Holiday", "Rent"},
+			expectedAmounts:    []int64{20000, 10000},
+			expectedUnmet:      50000,
+		},
+		{
+			name:               "nothing ready to assign",
+			available:          -5000,
+			expectedCategories: []string{},
+			expectedAmounts:    []int64{},
+			expectedUnmet:      80000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funded, amounts, unmet, err := fundingOfGoals(statuses, cc.NewMoney(tt.available, "USD"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			categories := []string{}
+			for _, status := range funded {
+				categories = append(categories, status.category)
+			}
+			values := []int64{}
+			for _, amount := range amounts {
+				values = append(values, amount.Amount)
+			}
+			if !slices.Equal(categories, tt.expectedCategories) {
+				t.Errorf("expected to fund %q, got %q", tt.expectedCategories, categories)
+			}
+			if !slices.Equal(values, tt.expectedAmounts) {
+				t.Errorf("expected amounts %v, got %v", tt.expectedAmounts, values)
+			}
+			if unmet.Amount != tt.expectedUnmet {
+				t.Errorf("expected %d unmet, got %d", tt.expectedUnmet, unmet.Amount)
+			}
+		})
+	}
+}
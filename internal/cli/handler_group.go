@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -18,7 +19,7 @@ func handlerGroup(s *State, c *handlerContext) error {
 		case "update":
 			return handleGroupUpdate(s, c)
 		case "delete":
-			return handleGroupDelete(s, c)
+			return middlewareConfirm(describeGroupDelete)(handleGroupDelete)(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -33,16 +34,31 @@ func handleGroupAdd(s *State, c *handlerContext) error {
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
 
-	err := s.Client.BudgetGroupCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetGroupCreateData{
+	payload := pgo.BudgetGroupCreateData{
 		MetaData: pgo.MetaData{
 			Name:  name,
 			Notes: notes,
 		},
-	})
+	}
+	if c.dryRun("BudgetGroupCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	gID, err := createFound(
+		func() ([]*pgo.Group, error) { return s.Client.BudgetGroups(bID, "") },
+		func(g *pgo.Group) string { return g.ID.String() },
+		func() error { return s.Client.BudgetGroupCreate(bID, payload) },
+	)
 	if err != nil {
 		return err
 	}
 
+	if gID != "" {
+		s.Session.UndoStack.push(
+			fmt.Sprintf("add group '%s' to budget %s", name, s.Session.ActiveBudget.Name),
+			func(s *State) error { return s.Client.BudgetGroupDelete(bID, gID) },
+		)
+	}
 	fmt.Println("Group " + name + " successfully created as user: " + s.Session.ActiveUser.Username)
 	fmt.Println("See it with: `group list`")
 	return nil
@@ -94,7 +110,7 @@ func handleGroupUpdate(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	group, err := findGroupByName(groupName, groups)
+	group, err := resolveGroup(groupName, groups)
 	if err != nil {
 		return err
 	}
@@ -110,19 +126,57 @@ func handleGroupUpdate(s *State, c *handlerContext) error {
 		payloadNotes = group.Notes
 	}
 
-	err = s.Client.BudgetGroupUpdate(s.Session.ActiveBudget.ID.String(), group.ID.String(), pgo.BudgetGroupUpdateData{
+	payload := pgo.BudgetGroupUpdateData{
 		MetaData: pgo.MetaData{
 			Name:  payloadName,
 			Notes: payloadNotes,
 		},
-	})
+	}
+	if c.dryRun("BudgetGroupUpdate", s.Session.ActiveBudget.ID.String(), group.ID.String(), payload) {
+		return nil
+	}
+	err = s.Client.BudgetGroupUpdate(s.Session.ActiveBudget.ID.String(), group.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	bID, prior := s.Session.ActiveBudget.ID.String(), *group
+	s.Session.UndoStack.push(
+		fmt.Sprintf("update group '%s' in budget %s", group.Name, s.Session.ActiveBudget.Name),
+		func(s *State) error {
+			return s.Client.BudgetGroupUpdate(bID, prior.ID.String(), pgo.BudgetGroupUpdateData{
+				MetaData: pgo.MetaData{
+					Name:  prior.Name,
+					Notes: prior.Notes,
+				},
+			})
+		},
+	)
 	fmt.Println("Group updated with new information")
 	return nil
 }
 
+func describeGroupDelete(s *State, c *handlerContext) (*destruction, error) {
+	name, _ := c.args.peek()
+
+	groups, err := s.GetGroups(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	group, err := resolveGroup(name, groups)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), "?group_name="+url.QueryEscape(group.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &destruction{
+		kind:    "group",
+		name:    group.Name,
+		effects: []string{fmt.Sprintf("%d category(ies) belong to the group", len(categories))},
+	}, nil
+}
+
 func handleGroupDelete(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 
@@ -130,11 +184,14 @@ func handleGroupDelete(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	group, err := findGroupByName(name, groups)
+	group, err := resolveGroup(name, groups)
 	if err != nil {
 		return err
 	}
 
+	if c.dryRun("BudgetGroupDelete", s.Session.ActiveBudget.ID.String(), group.ID.String()) {
+		return nil
+	}
 	err = s.Client.BudgetGroupDelete(s.Session.ActiveBudget.ID.String(), group.ID.String())
 	if err != nil {
 		return err
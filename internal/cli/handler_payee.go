@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -18,7 +19,7 @@ func handlerPayee(s *State, c *handlerContext) error {
 		case "update":
 			return handlePayeeUpdate(s, c)
 		case "delete":
-			return handlePayeeDelete(s, c)
+			return middlewareConfirm(describePayeeDelete)(handlePayeeDelete)(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -33,15 +34,30 @@ func handlePayeeAdd(s *State, c *handlerContext) error {
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
 
-	err := s.Client.BudgetPayeeCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetPayeeCreateData{
+	payload := pgo.BudgetPayeeCreateData{
 		MetaData: pgo.MetaData{
 			Name:  name,
 			Notes: notes,
 		},
-	})
+	}
+	if c.dryRun("BudgetPayeeCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	pID, err := createFound(
+		func() ([]*pgo.Payee, error) { return s.Client.BudgetPayees(bID, "") },
+		func(p *pgo.Payee) string { return p.ID.String() },
+		func() error { return s.Client.BudgetPayeeCreate(bID, payload) },
+	)
 	if err != nil {
 		return err
 	}
+	if pID != "" {
+		s.Session.UndoStack.push(
+			fmt.Sprintf("add payee '%s' to budget %s", name, s.Session.ActiveBudget.Name),
+			func(s *State) error { return s.Client.BudgetPayeeDelete(bID, pID, pgo.BudgetPayeeDeleteData{}) },
+		)
+	}
 	fmt.Println("Payee " + name + " successfully created as user: " + s.Session.ActiveUser.Username)
 	fmt.Println("See it with: `payee list`")
 	return nil
@@ -93,7 +109,7 @@ func handlePayeeUpdate(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	payee, err := findPayeeByName(payeeName, payees)
+	payee, err := resolvePayee(payeeName, payees)
 	if err != nil {
 		return err
 	}
@@ -109,19 +125,61 @@ func handlePayeeUpdate(s *State, c *handlerContext) error {
 		payloadNotes = payee.Notes
 	}
 
-	err = s.Client.BudgetPayeeUpdate(s.Session.ActiveBudget.ID.String(), payee.ID.String(), pgo.BudgetPayeeUpdateData{
+	payload := pgo.BudgetPayeeUpdateData{
 		MetaData: pgo.MetaData{
 			Name:  payloadName,
 			Notes: payloadNotes,
 		},
-	})
+	}
+	if c.dryRun("BudgetPayeeUpdate", s.Session.ActiveBudget.ID.String(), payee.ID.String(), payload) {
+		return nil
+	}
+	err = s.Client.BudgetPayeeUpdate(s.Session.ActiveBudget.ID.String(), payee.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	bID, prior := s.Session.ActiveBudget.ID.String(), *payee
+	s.Session.UndoStack.push(
+		fmt.Sprintf("update payee '%s' in budget %s", payee.Name, s.Session.ActiveBudget.Name),
+		func(s *State) error {
+			return s.Client.BudgetPayeeUpdate(bID, prior.ID.String(), pgo.BudgetPayeeUpdateData{
+				MetaData: pgo.MetaData{
+					Name:  prior.Name,
+					Notes: prior.Notes,
+				},
+			})
+		},
+	)
 	fmt.Println("Payee updated with new information")
 	return nil
 }
 
+func describePayeeDelete(s *State, c *handlerContext) (*destruction, error) {
+	name, _ := c.args.peek()
+
+	payees, err := s.GetPayees(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	payee, err := resolvePayee(name, payees)
+	if err != nil {
+		return nil, err
+	}
+	txnCount, err := s.countTxns(s.Session.ActiveBudget.ID.String(), url.Values{"payee_name": {payee.Name}})
+	if err != nil {
+		return nil, err
+	}
+	effect := fmt.Sprintf("%d transaction(s) are paid to the payee", txnCount)
+	if replacement, ok := c.cmd.opts["replacement"]; ok && len(replacement) > 0 {
+		effect += fmt.Sprintf(", and will be paid to '%s' instead", replacement[0])
+	}
+	return &destruction{
+		kind:    "payee",
+		name:    payee.Name,
+		effects: []string{effect},
+	}, nil
+}
+
 func handlePayeeDelete(s *State, c *handlerContext) error {
 	name, _ := c.args.pfx()
 	c.args.trackOptArgs(&c.cmd, "replacement")
@@ -131,14 +189,25 @@ func handlePayeeDelete(s *State, c *handlerContext) error {
 	if err != nil {
 		return err
 	}
-	payee, err := findPayeeByName(name, payees)
+	payee, err := resolvePayee(name, payees)
 	if err != nil {
 		return err
 	}
+	if newPayeeName != "" {
+		replacement, err := resolvePayee(newPayeeName, payees)
+		if err != nil {
+			return err
+		}
+		newPayeeName = replacement.Name
+	}
 
-	err = s.Client.BudgetPayeeDelete(s.Session.ActiveBudget.ID.String(), payee.ID.String(), pgo.BudgetPayeeDeleteData{
+	payload := pgo.BudgetPayeeDeleteData{
 		NewPayeeName: newPayeeName,
-	})
+	}
+	if c.dryRun("BudgetPayeeDelete", s.Session.ActiveBudget.ID.String(), payee.ID.String(), payload) {
+		return nil
+	}
+	err = s.Client.BudgetPayeeDelete(s.Session.ActiveBudget.ID.String(), payee.ID.String(), payload)
 	if err != nil {
 		return err
 	}
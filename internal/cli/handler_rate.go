@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/YouWantToPinch/pincher-cli/internal/exchange"
+)
+
+func handlerRate(s *State, c *handlerContext) error {
+	if val, ok := c.ctxValues["action"]; ok {
+		switch val {
+		case "list":
+			return handleRateList(s, c)
+		case "set":
+			return handleRateSet(s, c)
+		case "delete":
+			return handleRateDelete(s, c)
+		case "import":
+			return handleRateImport(s, c)
+		default:
+			return fmt.Errorf("action not implemented")
+		}
+	} else {
+		return fmt.Errorf("action was not saved to context")
+	}
+}
+
+func handleRateList(s *State, c *handlerContext) error {
+	c.args.trackOptArgs(&c.cmd, "from")
+	from, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "to")
+	to, _ := c.args.pfx()
+
+	table, err := exchange.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	rates := table.Filter(from, to)
+	if len(rates) == 0 {
+		fmt.Println("No exchange rates found.")
+		fmt.Println("Set one with: `rate set <from> <to> <rate>`")
+		return nil
+	}
+	fmt.Println("Exchange rates:")
+	rows := [][]string{}
+	for _, rate := range rates {
+		rows = append(rows, []string{rate.Date, rate.From, rate.To, rate.Rate})
+	}
+	fmt.Print(makeTable([]string{"DATE", "FROM", "TO", "RATE"}, rows))
+	return nil
+}
+
+func handleRateSet(s *State, c *handlerContext) error {
+	from, _ := c.args.pfx()
+	to, _ := c.args.pfx()
+	value, _ := c.args.pfx()
+	date, err := s.dayOption(c, "date")
+	if err != nil {
+		return err
+	}
+	rate, err := exchange.NewRate(from, to, value, date)
+	if err != nil {
+		return newCLIError(errKindUsage, "%v", err)
+	}
+
+	table, err := exchange.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would set rate %s -> %s to %s on %s\n", rate.From, rate.To, rate.Rate, rate.Date)
+		return nil
+	}
+	table.Set(rate)
+	err = table.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save exchange rates: %w", err)
+	}
+	fmt.Printf("Set rate: 1 %s = %s %s on %s\n", rate.From, rate.Rate, rate.To, rate.Date)
+	return nil
+}
+
+func handleRateDelete(s *State, c *handlerContext) error {
+	from, _ := c.args.pfx()
+	to, _ := c.args.pfx()
+	date, err := s.dayOption(c, "date")
+	if err != nil {
+		return err
+	}
+
+	table, err := exchange.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	if !table.Remove(from, to, date) {
+		return newCLIError(errKindNotFound, "no rate found from %s to %s on %s", from, to, date.Format(exchange.DateLayout))
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would delete rate %s -> %s on %s\n", from, to, date.Format(exchange.DateLayout))
+		return nil
+	}
+	err = table.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save exchange rates: %w", err)
+	}
+	fmt.Printf("Deleted rate %s -> %s on %s\n", from, to, date.Format(exchange.DateLayout))
+	return nil
+}
+
+func handleRateImport(s *State, c *handlerContext) error {
+	path, _ := c.args.pfx()
+	f, err := os.Open(path)
+	if err != nil {
+		return newCLIError(errKindPrecondition, "could not open rates file: %v", err)
+	}
+	defer f.Close()
+
+	table, err := exchange.ReadFromFile()
+	if err != nil {
+		return fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	count, err := table.ImportCSV(f)
+	if err != nil {
+		return newCLIError(errKindUsage, "could not import rates from %s: %v", path, err)
+	}
+	if c.globals.dryRun {
+		fmt.Printf("DRY RUN: would import %d rates from %s\n", count, path)
+		return nil
+	}
+	err = table.WriteToFile()
+	if err != nil {
+		return fmt.Errorf("could not save exchange rates: %w", err)
+	}
+	fmt.Printf("Imported %d rates from %s\n", count, path)
+	return nil
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func handlerReport(s *State, c *handlerContext) error {
+	if val, ok := c.ctxValues["action"]; ok {
+		switch val {
+		case "chart":
+			return handleReportChart(s, c)
+		default:
+			return fmt.Errorf("action not implemented")
+		}
+	} else {
+		return fmt.Errorf("action was not saved to context")
+	}
+}
+
+// chartKinds are the charts which report chart may draw.
+var chartKinds = map[string]func(s *State, c *handlerContext) error{
+	"spending": chartSpending,
+	"activity": chartActivity,
+	"networth": chartNetWorth,
+}
+
+func handleReportChart(s *State, c *handlerContext) error {
+	kind, _ := c.args.pfx()
+	draw, ok := chartKinds[kind]
+	if !ok {
+		kinds := []string{}
+		for name := range chartKinds {
+			kinds = append(kinds, name)
+		}
+		slices.Sort(kinds)
+		return newCLIError(errKindUsage, "unknown chart '%s'%s", kind, didYouMean(kind, kinds))
+	}
+	return draw(s, c)
+}
+
+// chartMonths reads the range of months given to a chart,
+// defaulting to the given number of months up to this one.
+func (s *State) chartMonths(c *handlerContext, count int) ([]time.Time, error) {
+	months, isRange, err := s.monthRange(c)
+	if err != nil || isRange {
+		return months, err
+	}
+	p, err := s.dateParser()
+	if err != nil {
+		return nil, err
+	}
+	thisMonth, err := p.Month("this month")
+	if err != nil {
+		return nil, err
+	}
+	for i := count - 1; i >= 0; i-- {
+		months = append(months, thisMonth.AddDate(0, -i, 0))
+	}
+	return months, nil
+}
+
+// chartWidth returns the width left for the marks of a chart
+// by the terminal, once the given width has been taken up.
+func chartWidth(taken int) int {
+	return min(max(getTerminalWidth()-taken, 10), 60)
+}
+
+// chartSpending draws the spending of each category in a month as bars.
+func chartSpending(s *State, c *handlerContext) error {
+	month, err := s.monthOption(c, "month")
+	if err != nil {
+		return err
+	}
+	reports, err := s.Client.BudgetCategoryReports(s.Session.ActiveBudget.ID.String(), month.Format(dates.DayLayout))
+	if err != nil {
+		return err
+	}
+
+	spending := []*pgo.CategoryReport{}
+	total := cc.NewMoney(0, s.homeCurrency())
+	for _, report := range reports {
+		if report.Activity < 0 {
+			spending = append(spending, report)
+			if total, err = total.Sub(cc.NewMoney(report.Activity, total.ISOCode)); err != nil {
+				return fmt.Errorf("could not total spending: %w", err)
+			}
+		}
+	}
+	if len(spending) == 0 {
+		fmt.Printf("Nothing spent in %s.\n", month.Format(dates.MonthLayout))
+		return nil
+	}
+	slices.SortFunc(spending, func(a, b *pgo.CategoryReport) int {
+		return cmp.Or(cmp.Compare(a.Activity, b.Activity), cmp.Compare(a.Name, b.Name))
+	})
+
+	labels, values, descriptions := []string{}, []int64{}, []string{}
+	for _, report := range spending {
+		labels = append(labels, report.Name)
+		values = append(values, -report.Activity)
+		share := float64(-report.Activity) / float64(total.Amount) * 100
+		descriptions = append(descriptions, fmt.Sprintf("%s (%.0f%%)", cc.Format(-report.Activity, total.ISOCode, true), share))
+	}
+	width := chartWidth(MaxOfStrings(labels...) + MaxOfStrings(descriptions...) + 6)
+	fmt.Printf("Spending by category in %s, %s in all:\n", month.Format(dates.MonthLayout), total)
+	fmt.Print(barChart(labels, values, descriptions, width, s.paint(green)))
+	return nil
+}
+
+// chartActivity draws the spending of each category over several months as sparklines.
+func chartActivity(s *State, c *handlerContext) error {
+	months, err := s.chartMonths(c, 6)
+	if err != nil {
+		return err
+	}
+	reports, err := s.fetchCategoryReports(months)
+	if err != nil {
+		return err
+	}
+	iso := s.homeCurrency()
+
+	spending := map[string][]int64{}
+	for i, monthReports := range reports {
+		for _, report := range monthReports {
+			if _, ok := spending[report.Name]; !ok {
+				spending[report.Name] = make([]int64, len(months))
+			}
+			spending[report.Name][i] = -report.Activity
+		}
+	}
+	totals := map[string]cc.Money{}
+	categories := []string{}
+	for category, values := range spending {
+		total := cc.NewMoney(0, iso)
+		for _, value := range values {
+			if total, err = total.Add(cc.NewMoney(value, iso)); err != nil {
+				return fmt.Errorf("could not total spending: %w", err)
+			}
+		}
+		if total.IsPositive() {
+			totals[category] = total
+			categories = append(categories, category)
+		}
+	}
+	if len(categories) == 0 {
+		fmt.Println("Nothing spent in these months.")
+		return nil
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		return cmp.Or(cmp.Compare(totals[b].Amount, totals[a].Amount), cmp.Compare(a, b))
+	})
+
+	paint := s.paint(green)
+	rows := [][]string{}
+	for _, category := range categories {
+		values := spending[category]
+		average := totals[category].Amount / int64(len(months))
+		rows = append(rows, []string{
+			category,
+			cc.Format(values[len(values)-1], iso, true),
+			cc.Format(average, iso, true),
+			paint(sparkline(values)),
+		})
+	}
+	fmt.Printf("Spending by category, %s to %s:\n", months[0].Format(dates.MonthLayout), months[len(months)-1].Format(dates.MonthLayout))
+	fmt.Print(makeTable([]string{"CATEGORY", "LATEST", "AVERAGE", "TREND"}, rows))
+	return nil
+}
+
+// chartNetWorth draws the sum of all account balances at the end of each month.
+func chartNetWorth(s *State, c *handlerContext) error {
+	months, err := s.chartMonths(c, 12)
+	if err != nil {
+		return err
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	txns, err := s.GetTxnsDetails(bID, "")
+	if err != nil {
+		return err
+	}
+	currencies, convert, err := s.balancesConverter()
+	if err != nil {
+		return err
+	}
+
+	worth, missing, err := netWorthByMonth(txns, months, currencies, s.homeCurrency(), convert)
+	if err != nil {
+		return err
+	}
+	amounts := make([]int64, len(worth))
+	labels := make([]string, len(worth))
+	for i, value := range worth {
+		amounts[i] = value.Amount
+		labels[i] = months[i].Format("Jan")
+	}
+	low, high := slices.Min(amounts), slices.Max(amounts)
+	iso := s.homeCurrency()
+	fmt.Printf("Net worth of budget %s, %s to %s:\n", s.Session.ActiveBudget.Name, months[0].Format(dates.MonthLayout), months[len(months)-1].Format(dates.MonthLayout))
+	fmt.Print(lineChart(amounts, labels, cc.Format(low, iso, true), cc.Format(high, iso, true), 8, 4, s.paint(green)))
+	change, err := worth[len(worth)-1].Sub(worth[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Now %s, %s since %s\n", worth[len(worth)-1], signed(change), months[0].Format(dates.MonthLayout))
+	if len(missing) > 0 {
+		fmt.Printf("  Excludes accounts kept in %v, having no rate to %s\n", missing, iso)
+	}
+	return nil
+}
+
+// netWorthByMonth sums the balances of all accounts at the end of each
+// of the given months, in the home currency. Balances in any other
+// currency are converted as of the end of the month; those which
+// cannot be are left out, and their currencies reported.
+func netWorthByMonth(txns []*pgo.TransactionDetail, months []time.Time, currencies map[string]string, home string,
+	convert homeConverter,
+) ([]cc.Money, []string, error) {
+	ordered := slices.Clone(txns)
+	sortTxns(ordered, "date", false)
+
+	balances := map[string]cc.Money{}
+	missing := []string{}
+	worth := make([]cc.Money, len(months))
+	next := 0
+	for i, month := range months {
+		end := month.AddDate(0, 1, -1)
+		for ; next < len(ordered) && ordered[next].TransactionDate.Format(dates.DayLayout) <= end.Format(dates.DayLayout); next++ {
+			txn := ordered[next]
+			iso, ok := currencies[txn.AccountName]
+			if !ok {
+				iso = home
+			}
+			balance, ok := balances[txn.AccountName]
+			if !ok {
+				balance = cc.NewMoney(0, iso)
+			}
+			var err error
+			if balances[txn.AccountName], err = balance.Add(cc.NewMoney(txn.TotalAmount, iso)); err != nil {
+				return nil, nil, fmt.Errorf("could not total account '%s': %w", txn.AccountName, err)
+			}
+		}
+
+		worth[i] = cc.NewMoney(0, home)
+		for _, balance := range balances {
+			converted, found, err := convert(balance, end)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !found {
+				if !slices.Contains(missing, balance.ISOCode) {
+					missing = append(missing, balance.ISOCode)
+				}
+				continue
+			}
+			if worth[i], err = worth[i].Add(converted); err != nil {
+				return nil, nil, fmt.Errorf("could not total net worth: %w", err)
+			}
+		}
+	}
+	slices.Sort(missing)
+	return worth, missing, nil
+}
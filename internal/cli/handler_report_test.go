@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestNetWorthByMonth(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	months := []time.Time{day(time.August, 1), day(time.September, 1), day(time.October, 1)}
+	txns := []*pgo.TransactionDetail{
+		{AccountName: "Checking", TransactionDate: day(time.September, 3), TotalAmount: -20000},
+		{AccountName: "Checking", TransactionDate: day(time.August, 1), TotalAmount: 100000},
+		{AccountName: "Euro Savings", TransactionDate: day(time.August, 31), TotalAmount: 50000},
+		{AccountName: "Yen Wallet", TransactionDate: day(time.October, 2), TotalAmount: 1000},
+	}
+	currencies := map[string]string{"Checking": "USD", "Euro Savings": "EUR", "Yen Wallet": "JPY"}
+	convert := func(balance cc.Money, on time.Time) (cc.Money, bool, error) {
+		switch balance.ISOCode {
+		case "USD":
+			return balance, true, nil
+		case "EUR":
+			converted, err := balance.Convert("USD", big.NewRat(2, 1))
+			return converted, true, err
+		default:
+			return cc.Money{}, false, nil
+		}
+	}
+
+	worth, missing, err := netWorthByMonth(txns, months, currencies, "USD", convert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []cc.Money{cc.NewMoney(200000, "USD"), cc.NewMoney(180000, "USD"), cc.NewMoney(180000, "USD")}
+	if !slices.EqualFunc(worth, expected, cc.Money.Equal) {
+		t.Errorf("expected net worth %v, got %v", expected, worth)
+	}
+	if !slices.Equal(missing, []string{"JPY"}) {
+		t.Errorf("expected missing currencies [JPY], got %v", missing)
+	}
+}
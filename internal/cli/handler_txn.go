@@ -2,12 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"net/url"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
-	"time"
 
 	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
 )
 
@@ -16,7 +18,7 @@ func handlerTxn(s *State, c *handlerContext) error {
 		switch val {
 		case "log":
 			return handleTxnLog(s, c)
-		case "transfer", "tfr":
+		case "transfer":
 			return handleTxnTransfer(s, c)
 		case "list":
 			return handleTxnList(s, c)
@@ -31,30 +33,45 @@ func handlerTxn(s *State, c *handlerContext) error {
 func handleTxnTransfer(s *State, c *handlerContext) error {
 	fromAccountName, _ := c.args.pfx()
 	toAccountName, _ := c.args.pfx()
-	amounts := map[string]int64{}
-	{
-		amount, _ := c.args.pfx()
-		parsedAmount, err := cc.Parse(amount, s.Config.CurrencyISOCode)
-		if err != nil {
-			return fmt.Errorf("could not log transfer: %w", err)
-		}
-		// Pincher-CLI handles transfers in a deliberately from->to manner
-		if parsedAmount < 0 {
-			return fmt.Errorf("could not log transfer: amount to transfer must be positive")
-		}
-		amounts["TRANSFER"] = int64(parsedAmount) * -1
+	amount, _ := c.args.pfx()
+	fromAccount, err := s.resolveAccountInView(fromAccountName)
+	if err != nil {
+		return fmt.Errorf("could not log transfer: %w", err)
+	}
+	toAccount, err := s.resolveAccountInView(toAccountName)
+	if err != nil {
+		return fmt.Errorf("could not log transfer: %w", err)
+	}
+	fromAccountName, toAccountName = fromAccount.Name, toAccount.Name
+	// a transfer moves a single amount, so both sides must share a currency
+	iso := s.accountCurrency(fromAccount.ID.String())
+	if toISO := s.accountCurrency(toAccount.ID.String()); toISO != iso {
+		return newCLIError(errKindUsage, "could not log transfer: '%s' is kept in %s, but '%s' is kept in %s", fromAccountName, iso, toAccountName, toISO)
 	}
+	transferred, err := cc.EvaluateMoney(amount, iso)
+	if err != nil {
+		return fmt.Errorf("could not log transfer: %w", err)
+	}
+	// Pincher-CLI handles transfers in a deliberately from->to manner
+	if transferred.IsNegative() {
+		return fmt.Errorf("could not log transfer: amount to transfer must be positive")
+	}
+	outflow, err := transferred.Neg()
+	if err != nil {
+		return fmt.Errorf("could not log transfer: %w", err)
+	}
+	amounts := map[string]int64{"TRANSFER": outflow.Amount}
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
-	c.args.trackOptArgs(&c.cmd, "date")
-	transactionDate, err := c.args.pfx()
+	transactionDay, err := s.dayOption(c, "date")
 	if err != nil {
-		transactionDate = time.Now().Format("2006-01-02")
+		return fmt.Errorf("could not log transfer: %w", err)
 	}
+	transactionDate := transactionDay.Format(dates.DayLayout)
 	c.args.trackOptArgs(&c.cmd, "cleared")
 	isCleared, _ := c.args.pfx()
 
-	err = s.Client.BudgetTransactionCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetTransactionCreateData{
+	payload := pgo.BudgetTransactionCreateData{
 		AccountName:         fromAccountName,
 		TransferAccountName: toAccountName,
 		TransactionDate:     transactionDate,
@@ -62,10 +79,16 @@ func handleTxnTransfer(s *State, c *handlerContext) error {
 		Notes:               notes,
 		Cleared:             isCleared == "SET",
 		Amounts:             amounts,
-	})
+	}
+	if c.dryRun("BudgetTransactionCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	before := s.txnIDsForUndo(fromAccountName, toAccountName)
+	err = s.Client.BudgetTransactionCreate(s.Session.ActiveBudget.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	s.recordTxnUndo(fmt.Sprintf("transfer %s from '%s' to '%s'", transferred, fromAccountName, toAccountName), before, fromAccountName, toAccountName)
 	fmt.Printf("New transfer logged to accounts: %s -> %s\n", fromAccountName, toAccountName)
 	return nil
 }
@@ -75,17 +98,28 @@ func handleTxnLog(s *State, c *handlerContext) error {
 	payeeName, _ := c.args.pfx()
 	totalAmountString, _ := c.args.pfx()
 	category, _ := c.args.pfx()
-	c.args.trackOptArgs(&c.cmd, "date")
-	transactionDate, err := c.args.pfx()
+	account, err := s.resolveAccountInView(accountName)
 	if err != nil {
-		transactionDate = time.Now().Format("2006-01-02")
+		return err
+	}
+	accountName = account.Name
+	iso := s.accountCurrency(account.ID.String())
+	payeeName, err = s.resolvePayeeName(payeeName)
+	if err != nil {
+		return err
 	}
+	transactionDay, err := s.dayOption(c, "date")
+	if err != nil {
+		return err
+	}
+	transactionDate := transactionDay.Format(dates.DayLayout)
 	c.args.trackOptArgs(&c.cmd, "notes")
 	notes, _ := c.args.pfx()
 	c.args.trackOptArgs(&c.cmd, "cleared")
 	isCleared, _ := c.args.pfx()
 
 	amounts := map[string]int64{}
+	var logged cc.Money
 	c.args.trackOptArgs(&c.cmd, "split")
 	splitArg, err := c.args.pfx()
 	if err == nil {
@@ -95,38 +129,52 @@ func handleTxnLog(s *State, c *handlerContext) error {
 			if len(splits) == 0 {
 				return fmt.Errorf("split option used, but no splits provided")
 			}
-			var splitsTotal int64
+			splitsTotal := cc.NewMoney(0, iso)
 			for _, split := range splits {
-				pair := strings.Split(split, "=")
-				if len(pair) != 2 {
+				// cut at the first '=' only, leaving
+				// expressions like Dining==84.20/3 intact
+				category, amount, found := strings.Cut(split, "=")
+				if !found {
 					return fmt.Errorf("could not parse one or more splits")
 				}
-				category, amount := pair[0], pair[1]
-				parsedAmount, err := cc.Parse(amount, s.Config.CurrencyISOCode)
+				category, err := s.resolveCategoryName(category)
 				if err != nil {
 					return err
 				}
-				amounts[category] = int64(parsedAmount)
-				splitsTotal += int64(parsedAmount)
+				splitAmount, err := cc.EvaluateMoney(amount, iso)
+				if err != nil {
+					return err
+				}
+				amounts[category] = splitAmount.Amount
+				splitsTotal, err = splitsTotal.Add(splitAmount)
+				if err != nil {
+					return fmt.Errorf("could not total splits: %w", err)
+				}
 			}
-			totalAmount, err := cc.Parse(totalAmountString, s.Config.CurrencyISOCode)
+			totalAmount, err := cc.EvaluateMoney(totalAmountString, iso)
 			if err != nil {
 				return err
 			}
-			if splitsTotal != int64(totalAmount) {
-				return fmt.Errorf("split amounts (%d) do not amount to total: %d", splitsTotal, totalAmount)
+			if !splitsTotal.Equal(totalAmount) {
+				return fmt.Errorf("split amounts (%s) do not amount to total: %s", splitsTotal, totalAmount)
 			}
+			logged = totalAmount
 		} else {
 			return fmt.Errorf("substitute 'split' for the category argument to use the --splits option")
 		}
 	} else {
-		totalAmount, err := cc.Parse(totalAmountString, s.Config.CurrencyISOCode)
+		category, err := s.resolveCategoryName(category)
 		if err != nil {
 			return err
 		}
-		amounts[category] = int64(totalAmount)
+		totalAmount, err := cc.EvaluateMoney(totalAmountString, iso)
+		if err != nil {
+			return err
+		}
+		amounts[category] = totalAmount.Amount
+		logged = totalAmount
 	}
-	err = s.Client.BudgetTransactionCreate(s.Session.ActiveBudget.ID.String(), pgo.BudgetTransactionCreateData{
+	payload := pgo.BudgetTransactionCreateData{
 		AccountName:         accountName,
 		TransferAccountName: "",
 		TransactionDate:     transactionDate,
@@ -134,10 +182,16 @@ func handleTxnLog(s *State, c *handlerContext) error {
 		Notes:               notes,
 		Cleared:             isCleared == "SET",
 		Amounts:             amounts,
-	})
+	}
+	if c.dryRun("BudgetTransactionCreate", s.Session.ActiveBudget.ID.String(), payload) {
+		return nil
+	}
+	before := s.txnIDsForUndo(accountName)
+	err = s.Client.BudgetTransactionCreate(s.Session.ActiveBudget.ID.String(), payload)
 	if err != nil {
 		return err
 	}
+	s.recordTxnUndo(fmt.Sprintf("log %s to account '%s' for payee '%s'", logged, accountName, payeeName), before, accountName)
 	fmt.Printf("New transaction logged to account: %s\n", accountName)
 	return nil
 }
@@ -150,45 +204,232 @@ func handleTxnList(s *State, c *handlerContext) error {
 	c.args.trackOptArgs(&c.cmd, "payee")
 	payeeName, _ := c.args.pfx()
 
-	txnQuery := ""
-	if len(accountName+categoryName+payeeName) > 0 {
-		params := url.Values{}
-		for k, v := range map[string]string{"account_name": accountName, "category_name": categoryName, "payee_name": payeeName} {
-			if v != "" {
-				params.Add(k, v)
-			}
+	var err error
+	// amounts to filter by are read in the currency
+	// of the account filtered by, if any
+	iso := s.homeCurrency()
+	if accountName != "" {
+		account, err := s.resolveAccountInView(accountName)
+		if err != nil {
+			return err
 		}
-		txnQuery = params.Encode()
-		txnQuery = "?" + txnQuery
+		accountName = account.Name
+		iso = s.accountCurrency(account.ID.String())
+	}
+	if categoryName != "" {
+		if categoryName, err = s.resolveCategoryName(categoryName); err != nil {
+			return err
+		}
+	}
+	if payeeName != "" {
+		if payeeName, err = s.resolvePayeeName(payeeName); err != nil {
+			return err
+		}
+	}
+
+	filter, err := s.txnListFilter(c, iso)
+	if err != nil {
+		return err
+	}
+
+	c.args.trackOptArgs(&c.cmd, "sort")
+	sortBy, _ := c.args.pfx()
+	if sortBy == "" {
+		sortBy = "date"
+	}
+	if _, ok := txnOrderings[sortBy]; !ok {
+		return newCLIError(errKindUsage, "cannot sort by '%s'; sort by date, amount or payee", sortBy)
+	}
+	c.args.trackOptArgs(&c.cmd, "reverse")
+	reverse, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "columns")
+	columnNames, _ := c.args.pfx()
+	columns, err := chooseTxnColumns(columnNames)
+	if err != nil {
+		return err
+	}
+	c.args.trackOptArgs(&c.cmd, "expand-splits")
+	expandSplits, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "limit")
+	limitArg, _ := c.args.pfx()
+	limit := 0
+	if limitArg != "" {
+		limit, err = strconv.Atoi(limitArg)
+		if err != nil || limit <= 0 {
+			return newCLIError(errKindUsage, "limit must be a positive whole number")
+		}
+	}
+
+	// the server filters by name and date;
+	// everything else is filtered here
+	params := url.Values{}
+	for k, v := range map[string]string{
+		"account_name":  accountName,
+		"category_name": categoryName,
+		"payee_name":    payeeName,
+		"start_date":    filter.startDate,
+		"end_date":      filter.endDate,
+	} {
+		if v != "" {
+			params.Add(k, v)
+		}
+	}
+	txnQuery := ""
+	if len(params) > 0 {
+		txnQuery = "?" + params.Encode()
 	}
 
 	txns, err := s.GetTxnsDetails(s.Session.ActiveBudget.ID.String(), txnQuery)
 	if err != nil {
 		return err
 	}
+	// dates are checked once more, should the
+	// server have ignored some of the query
+	txns = filterTxns(txns, filter)
 	if len(txns) == 0 {
 		fmt.Printf("No transactions found under budget %s.\n", s.Session.ActiveBudget.Name)
 		return nil
 	}
-	fmt.Printf("%s transactions:\n", s.Session.ActiveBudget.Name)
-	sort.Slice(txns, func(i, j int) bool {
-		return txns[i].TransactionDate.Before(txns[j].TransactionDate)
-	})
-	// const uuidLength = 36
-	maxLenDate := MaxOfStrings(ExtractStrings(txns, func(t *pgo.TransactionDetail) string { return t.TransactionDate.Format("2006-01-02") })...)
-	maxLenAmount := MaxOfStrings(ExtractStrings(txns, func(t *pgo.TransactionDetail) string {
-		return cc.Format(t.TotalAmount, s.Config.CurrencyISOCode, true)
-	})...)
-	maxLenNotes := MaxOfStrings(ExtractStrings(txns, func(t *pgo.TransactionDetail) string { return firstNChars(t.Notes, 25) + "..." })...)
-	fmt.Printf("  %-*s | %-*s | %s\n", maxLenDate, "DATE", maxLenAmount, "AMOUNT", "NOTES")
-	fmt.Printf("  %s-+-%s-+-%s\n", nDashes(maxLenDate), nDashes(maxLenAmount), nDashes(maxLenNotes))
-	for _, txn := range txns {
-		fmt.Printf("  %-*s  %-*s   %s\n", maxLenDate, txn.TransactionDate.Format("2006-01-02"), maxLenAmount, cc.Format(txn.TotalAmount, s.Config.CurrencyISOCode, true), firstNChars(txn.Notes, 25))
+	sortTxns(txns, sortBy, reverse == "SET")
+	if limit > 0 && len(txns) > limit {
+		txns = txns[:limit]
 	}
+	rows, err := s.txnRows(txns, columns)
+	if err != nil {
+		return err
+	}
+	table := [][]string{}
+	for _, row := range rows {
+		cells := ExtractStrings(columns, func(col txnColumn) string { return col.cell(row) })
+		table = append(table, cells)
+		if expandSplits == "SET" {
+			table = append(table, splitRows(row, columns)...)
+		}
+	}
+	fmt.Printf("%s transactions:\n", s.Session.ActiveBudget.Name)
+	fmt.Print(makeTable(ExtractStrings(columns, func(col txnColumn) string { return col.header }), table))
 
 	return nil
 }
 
+// txnRows pairs transactions with the currency of their account and,
+// should the columns include it, the running balance of that account.
+func (s *State) txnRows(txns []*pgo.TransactionDetail, columns []txnColumn) ([]txnRow, error) {
+	bID := s.Session.ActiveBudget.ID.String()
+	accounts, err := s.GetAccounts(bID, "")
+	if err != nil {
+		return nil, err
+	}
+	currencies := map[string]string{}
+	for _, account := range accounts {
+		currencies[account.Name] = s.accountCurrency(account.ID.String())
+	}
+
+	var balances map[string]int64
+	if slices.ContainsFunc(columns, func(col txnColumn) bool { return col.name == "balance" }) {
+		// balances run from the first transaction of each
+		// account, whichever transactions are listed
+		all, err := s.GetTxnsDetails(bID, "")
+		if err != nil {
+			return nil, err
+		}
+		balances = runningBalances(all)
+	}
+
+	rows := make([]txnRow, len(txns))
+	for i, txn := range txns {
+		iso, ok := currencies[txn.AccountName]
+		if !ok {
+			iso = s.homeCurrency()
+		}
+		rows[i] = txnRow{txn: txn, iso: iso, balance: balances[txn.ID.String()]}
+	}
+	return rows, nil
+}
+
+// txnListFilter reads the options of txn list
+// which filter transactions by their details.
+func (s *State) txnListFilter(c *handlerContext, ISOCode string) (txnFilter, error) {
+	filter := txnFilter{}
+
+	c.args.trackOptArgs(&c.cmd, "dates")
+	start, _ := c.args.pfx()
+	end, _ := c.args.pfx()
+	if start != "" {
+		p, err := s.dateParser()
+		if err != nil {
+			return filter, err
+		}
+		startDay, err := p.Day(start)
+		if err != nil {
+			return filter, newCLIError(errKindUsage, "%v", err)
+		}
+		endDay, err := p.Day(end)
+		if err != nil {
+			return filter, newCLIError(errKindUsage, "%v", err)
+		}
+		if endDay.Before(startDay) {
+			return filter, newCLIError(errKindUsage, "end date %s comes before start date %s", endDay.Format(dates.DayLayout), startDay.Format(dates.DayLayout))
+		}
+		filter.startDate, filter.endDate = startDay.Format(dates.DayLayout), endDay.Format(dates.DayLayout)
+	}
+
+	for option, bound := range map[string]**int64{"min": &filter.minAmount, "max": &filter.maxAmount} {
+		c.args.trackOptArgs(&c.cmd, option)
+		amount, _ := c.args.pfx()
+		if amount == "" {
+			continue
+		}
+		parsed, err := cc.Evaluate(amount, ISOCode)
+		if err != nil {
+			return filter, newCLIError(errKindUsage, "%v", err)
+		}
+		if parsed < 0 {
+			parsed = -parsed
+		}
+		*bound = &parsed
+	}
+	if filter.minAmount != nil && filter.maxAmount != nil && *filter.minAmount > *filter.maxAmount {
+		return filter, newCLIError(errKindUsage, "--min may not be greater than --max")
+	}
+
+	c.args.trackOptArgs(&c.cmd, "cleared")
+	cleared, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "uncleared")
+	uncleared, _ := c.args.pfx()
+	if cleared == "SET" && uncleared == "SET" {
+		return filter, newCLIError(errKindUsage, "use either --cleared or --uncleared, not both")
+	}
+	if cleared == "SET" || uncleared == "SET" {
+		isCleared := cleared == "SET"
+		filter.cleared = &isCleared
+	}
+
+	c.args.trackOptArgs(&c.cmd, "notes")
+	filter.notes, _ = c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "transfers")
+	transfers, _ := c.args.pfx()
+	filter.transfersOnly = transfers == "SET"
+
+	c.args.trackOptArgs(&c.cmd, "group")
+	groupName, _ := c.args.pfx()
+	if groupName != "" {
+		groupName, err := s.resolveGroupName(groupName)
+		if err != nil {
+			return filter, err
+		}
+		categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), "?group_name="+url.QueryEscape(groupName))
+		if err != nil {
+			return filter, err
+		}
+		filter.categories = map[string]bool{}
+		for _, category := range categories {
+			filter.categories[category.Name] = true
+		}
+	}
+	return filter, nil
+}
+
 // TODO:
 // Add transaction updates and deletes.
 //
@@ -204,3 +445,50 @@ func handleTxnList(s *State, c *handlerContext) error {
 // transactions, and when the limit is hit near the bottom, a message is
 // sent outside the model to another goroutine that requests the next
 // (LIMIT) amount of transactions, then adds it the the list.
+
+// txnIDsForUndo returns the IDs of transactions within the given
+// accounts of the budget in view, bypassing the cache so that newly
+// logged transactions may be told apart from existing ones.
+// It returns nil if they could not be retrieved.
+func (s *State) txnIDsForUndo(accountNames ...string) map[string]bool {
+	ids := map[string]bool{}
+	for _, name := range accountNames {
+		txns, err := s.Client.BudgetTransactions(s.Session.ActiveBudget.ID.String(), "?"+url.Values{"account_name": {name}}.Encode())
+		if err != nil {
+			slog.Warn("could not retrieve transactions to support undo", slog.String("error", err.Error()))
+			return nil
+		}
+		for _, txn := range txns {
+			ids[txn.ID.String()] = true
+		}
+	}
+	return ids
+}
+
+// recordTxnUndo makes undoable any transactions which have appeared
+// in the given accounts since the IDs seen beforehand were retrieved.
+func (s *State) recordTxnUndo(description string, before map[string]bool, accountNames ...string) {
+	after := s.txnIDsForUndo(accountNames...)
+	if before == nil || after == nil {
+		return
+	}
+	newIDs := []string{}
+	for id := range after {
+		if !before[id] {
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		slog.Warn("could not find logged transaction to support undo", slog.String("description", description))
+		return
+	}
+	bID := s.Session.ActiveBudget.ID.String()
+	s.Session.UndoStack.push(description+" in budget "+s.Session.ActiveBudget.Name, func(s *State) error {
+		for _, id := range newIDs {
+			if err := s.Client.BudgetTransactionDelete(bID, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
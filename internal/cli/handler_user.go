@@ -6,6 +6,9 @@ import (
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
 )
 
+// redactedPassword stands in for passwords in output.
+const redactedPassword = "********"
+
 func handlerUser(s *State, c *handlerContext) error {
 	if val, ok := c.ctxValues["action"]; ok {
 		switch val {
@@ -18,7 +21,7 @@ func handlerUser(s *State, c *handlerContext) error {
 		case "update":
 			return handleUserUpdate(s, c)
 		case "delete":
-			return handleUserDelete(s, c)
+			return middlewareConfirm(describeUserDelete)(handleUserDelete)(s, c)
 		default:
 			return fmt.Errorf("action not implemented")
 		}
@@ -33,12 +36,16 @@ func handleUserAdd(s *State, c *handlerContext) error {
 	retypedPassword, _ := c.args.pfx()
 
 	if password != retypedPassword {
-		return fmt.Errorf("password fields did not match")
+		return newCLIError(errKindUsage, "password fields did not match")
 	}
-	err := s.Client.UserCreate(pgo.UserCreateData{
+	payload := pgo.UserCreateData{
 		Username: username,
 		Password: password,
-	})
+	}
+	if c.dryRun("UserCreate", pgo.UserCreateData{Username: payload.Username, Password: redactedPassword}) {
+		return nil
+	}
+	err := s.Client.UserCreate(payload)
 	if err != nil {
 		return err
 	}
@@ -101,14 +108,18 @@ func handleUserUpdate(s *State, c *handlerContext) error {
 	} else {
 		retypedNewPassword, _ := c.args.pfx()
 		if newPassword != retypedNewPassword {
-			return fmt.Errorf("fields for new password did not match")
+			return newCLIError(errKindUsage, "fields for new password did not match")
 		}
 	}
 
-	err = s.Client.UserUpdate(pgo.UserUpdateData{
+	payload := pgo.UserUpdateData{
 		Username: newUsername,
 		Password: newPassword,
-	})
+	}
+	if c.dryRun("UserUpdate", pgo.UserUpdateData{Username: payload.Username, Password: redactedPassword}) {
+		return nil
+	}
+	err = s.Client.UserUpdate(payload)
 	if err != nil {
 		return err
 	}
@@ -117,18 +128,33 @@ func handleUserUpdate(s *State, c *handlerContext) error {
 	return nil
 }
 
+func describeUserDelete(s *State, c *handlerContext) (*destruction, error) {
+	username, _ := c.args.peek()
+
+	// the budgets of another user are not known to this session,
+	// so there are no effects which may be counted up front
+	return &destruction{
+		kind: "user",
+		name: username,
+	}, nil
+}
+
 func handleUserDelete(s *State, c *handlerContext) error {
 	username, _ := c.args.pfx()
 	password, _ := c.args.pfx()
 	retypedPassword, _ := c.args.pfx()
 
 	if password != retypedPassword {
-		return fmt.Errorf("password fields did not match")
+		return newCLIError(errKindUsage, "password fields did not match")
 	}
-	err := s.Client.UserDelete(pgo.UserDeleteData{
+	payload := pgo.UserDeleteData{
 		Username: username,
 		Password: password,
-	})
+	}
+	if c.dryRun("UserDelete", pgo.UserDeleteData{Username: payload.Username, Password: redactedPassword}) {
+		return nil
+	}
+	err := s.Client.UserDelete(payload)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"sort"
+	"strings"
+
+	"golang.org/x/term"
 )
 
 // ========= MIDDLEWARE ==============
@@ -14,19 +17,94 @@ func middlewareValidateAction(next HandlerFunc) HandlerFunc {
 		var err error
 		action, _ := c.args.pfx()
 		if action == "" {
-			err = fmt.Errorf("no action specified")
+			err = newCLIError(errKindUsage, "no action specified")
 			s.Session.CommandRegistry.handlers[c.cmd.name].help()
 			return err
-		} else if _, found := findCMDElementWithName(s.Session.CommandRegistry.handlers[c.cmd.name].actions, action); !found {
-			err = fmt.Errorf("invalid action for command '%s': %s", c.cmd.name, action)
+		}
+		actions := s.Session.CommandRegistry.handlers[c.cmd.name].actions
+		el, found := findCMDElementWithName(actions, action)
+		if !found {
+			err = newCLIError(errKindUsage, "invalid action for command '%s': %s%s", c.cmd.name, action, didYouMean(action, elementNames(actions)))
 			s.Session.CommandRegistry.handlers[c.cmd.name].help()
 			return err
 		}
-		c.ctxValues["action"] = action
+		c.ctxValues["action"] = el.name
 		return next(s, c)
 	})
 }
 
+// destruction describes what a destructive action is about
+// to destroy, so that a user may confirm it beforehand.
+type destruction struct {
+	kind    string   // kind of resource, as in "account"
+	name    string   // name a user must type to confirm
+	effects []string // consequences of the action, shown to the user
+}
+
+// describeFunc describes what a handler would destroy, given its context.
+// It returns a nil destruction if no confirmation is needed.
+type describeFunc func(s *State, c *handlerContext) (*destruction, error)
+
+// middlewareConfirm asks the user to confirm a destructive action by typing
+// the name of what it destroys before the action may run, as confirm would.
+func middlewareConfirm(describe describeFunc) func(HandlerFunc) HandlerFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return HandlerFunc(func(s *State, c *handlerContext) error {
+			if c.globals.yes || c.globals.dryRun {
+				return next(s, c)
+			}
+			d, err := describe(s, c)
+			if err != nil {
+				return err
+			}
+			if d == nil {
+				return next(s, c)
+			}
+
+			var question strings.Builder
+			fmt.Fprintf(&question, "This will permanently delete %s '%s'.\n", d.kind, d.name)
+			for _, effect := range d.effects {
+				question.WriteString("  - " + effect + "\n")
+			}
+			fmt.Fprintf(&question, "Type the %s name to confirm: ", d.kind)
+			confirmed, err := s.confirm(c, fmt.Sprintf("delete %s '%s'", d.kind, d.name), question.String(),
+				func(answer string) bool { return answer == d.name })
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return newCLIError(errKindPrecondition, "confirmation did not match; %s '%s' was not deleted", d.kind, d.name)
+			}
+			return next(s, c)
+		})
+	}
+}
+
+// confirm asks the user the given question before an action may run,
+// reporting whether their answer is accepted. Confirmation is skipped
+// with the --yes and --dry-run global options, and is otherwise refused
+// whenever input does not come from a terminal. The action is described
+// as in "delete account 'Checking'", for the error returned on refusal.
+func (s *State) confirm(c *handlerContext, action, question string, accept func(answer string) bool) (bool, error) {
+	if c.globals.yes || c.globals.dryRun {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, newCLIError(errKindPrecondition, "refusing to %s without confirmation; input is not a terminal (use --yes)", action)
+	}
+	answer, err := s.readLine(question)
+	if err != nil {
+		return false, fmt.Errorf("could not read confirmation: %w", err)
+	}
+	return accept(answer), nil
+}
+
+// isYes accepts an answer of yes to a [y/N] question.
+func isYes(answer string) bool {
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
+
 // =========== HANDLERS =============
 
 func handlerClear(s *State, c *handlerContext) error {
@@ -79,12 +157,26 @@ func handlerHelp(s *State, c *handlerContext) error {
 		column1 := []string{}
 		column2 := []string{}
 		for _, reg := range registered {
-			column1 = append(column1, fmt.Sprintf("  %s", reg.name))
+			column1 = append(column1, fmt.Sprintf("  %s", reg.nameWithAliases()))
 			column2 = append(column2, reg.description)
 		}
 
 		fmt.Println(makeAlignedTable(column1, column2))
 
+		if len(s.Config.Aliases) > 0 {
+			fmt.Println("ALIASES: ")
+			fmt.Println(makeAliasTable(s.Config.Aliases))
+		}
+
+		fmt.Println("GLOBAL OPTIONS: ")
+		column1 = []string{}
+		column2 = []string{}
+		for _, opt := range makeGlobalOptions() {
+			column1 = append(column1, "  --"+strings.TrimSuffix(opt.usage(false), "\n"))
+			column2 = append(column2, opt.description)
+		}
+		fmt.Println(makeAlignedTable(column1, column2))
+
 		return nil
 	}
 	return fmt.Errorf("could not get help for command: 'help'")
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestConfirm(t *testing.T) {
+	s := &State{}
+	tests := []struct {
+		name      string
+		globals   globalOptions
+		confirmed bool
+		wantKind  errorKind
+		wantErr   bool
+	}{
+		{name: "yes", globals: globalOptions{yes: true}, confirmed: true},
+		{name: "dry run", globals: globalOptions{dryRun: true}, confirmed: true},
+		{name: "no terminal", wantErr: true, wantKind: errKindPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr && term.IsTerminal(int(os.Stdin.Fd())) {
+				t.Skip("input comes from a terminal")
+			}
+			c := &handlerContext{globals: tt.globals}
+			confirmed, err := s.confirm(c, "assign", "Make these assignments? [y/N]: ", isYes)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr && classifyError(err).kind != tt.wantKind {
+				t.Errorf("expected error kind %s, but got %s", tt.wantKind, classifyError(err).kind)
+			}
+			if confirmed != tt.confirmed {
+				t.Errorf("expected confirmed: %v, but got: %v", tt.confirmed, confirmed)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package cli
 
-import "strings"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 func cleanInput(text string) []string {
 	fields := []string{}
@@ -17,13 +21,95 @@ func cleanInput(text string) []string {
 	return fields
 }
 
-// returns the first cmdElement with the given name from a slice of cmdElements
+// returns the first cmdElement with the given name or alias from a slice of cmdElements
 func findCMDElementWithName(elements []cmdElement, name string) (*cmdElement, bool) {
 	for i := range elements {
 		el := &elements[i]
-		if el.name == name {
+		if el.hasName(name) {
 			return el, true
 		}
 	}
 	return nil, false
 }
+
+// editDistance returns the Levenshtein distance between two strings,
+// ignoring case.
+func editDistance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// closestMatches returns up to n candidates that are near enough
+// to the target to have plausibly been meant by it, closest first.
+func closestMatches(target string, candidates []string, n int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+	// allow roughly one typo for every three characters
+	threshold := max((len([]rune(target))+1)/3, 1)
+	matches := []match{}
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		distance := editDistance(target, candidate)
+		if distance <= threshold || strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(target)) {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+	closest := []string{}
+	for i := 0; i < len(matches) && i < n; i++ {
+		closest = append(closest, matches[i].candidate)
+	}
+	return closest
+}
+
+// didYouMean returns a suggestion to append to an error message,
+// listing the candidates closest to the target, if there are any.
+func didYouMean(target string, candidates []string) string {
+	closest := closestMatches(target, candidates, 3)
+	switch len(closest) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("; did you mean '%s'?", closest[0])
+	default:
+		return fmt.Sprintf("; did you mean one of: '%s'?", strings.Join(closest, "', '"))
+	}
+}
+
+// elementNames returns the names and aliases of the given cmdElements.
+func elementNames(elements []cmdElement) []string {
+	names := []string{}
+	for _, el := range elements {
+		names = append(names, el.name)
+		names = append(names, el.aliases...)
+	}
+	return names
+}
@@ -52,3 +52,52 @@ func TestCleanInput(t *testing.T) {
 		})
 	}
 }
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "category", b: "category", expected: 0},
+		{a: "categroy", b: "category", expected: 2},
+		{a: "Chekcing", b: "Checking", expected: 2},
+		{a: "checking", b: "Checking", expected: 0},
+		{a: "", b: "list", expected: 4},
+		{a: "lst", b: "list", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" -> "+tt.b, func(t *testing.T) {
+			if actual := editDistance(tt.a, tt.b); actual != tt.expected {
+				t.Errorf("expected distance %d, but got %d", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	candidates := []string{"account", "budget", "category", "group", "payee", "txn", "help", "clear"}
+	tests := []struct {
+		target   string
+		expected []string
+	}{
+		{target: "categroy", expected: []string{"category"}},
+		{target: "acount", expected: []string{"account"}},
+		{target: "gruop", expected: []string{"group"}},
+		{target: "xyzzy", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			actual := closestMatches(tt.target, candidates, 3)
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("expected matches %v, but got %v", tt.expected, actual)
+			}
+			for i := range actual {
+				if actual[i] != tt.expected[i] {
+					t.Errorf("expected matches %v, but got %v", tt.expected, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	"github.com/YouWantToPinch/pincher-cli/internal/exchange"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// homeConverter converts an amount of money into the home
+// currency as of a date, reporting false if no rate is known.
+type homeConverter func(amount cc.Money, on time.Time) (cc.Money, bool, error)
+
+// readiness is the standing of a budget in a month: the money that
+// came in, the money assigned to categories, and the money left to
+// assign, being all that is held in accounts less that held in
+// categories.
+type readiness struct {
+	income   cc.Money
+	assigned cc.Money
+	ready    cc.Money
+	// missing holds the currencies of accounts left out
+	// of the figures, having no rate to the home currency
+	missing []string
+}
+
+// balancesConverter returns the currency of each account of the budget
+// in view by name, along with a converter into its currency.
+func (s *State) balancesConverter() (map[string]string, homeConverter, error) {
+	accounts, err := s.GetAccounts(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, nil, err
+	}
+	rates, err := exchange.ReadFromFile()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read exchange rates: %w", err)
+	}
+	currencies := map[string]string{}
+	for _, account := range accounts {
+		currencies[account.Name] = s.accountCurrency(account.ID.String())
+	}
+	convert := func(amount cc.Money, on time.Time) (cc.Money, bool, error) {
+		converted, found, err := s.toHomeCurrency(rates, amount, on)
+		return converted.amount, found, err
+	}
+	return currencies, convert, nil
+}
+
+// readyToAssign works out the standing of the budget in view for
+// the month of the given report.
+func (s *State) readyToAssign(month time.Time, report *pgo.BudgetReport) (readiness, error) {
+	txns, err := s.GetTxnsDetails(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return readiness{}, err
+	}
+	currencies, convert, err := s.balancesConverter()
+	if err != nil {
+		return readiness{}, err
+	}
+	iso := s.homeCurrency()
+	return monthReadiness(txns, month, currencies, iso, convert,
+		cc.NewMoney(report.Assigned, iso), cc.NewMoney(report.Balance, iso))
+}
+
+// monthReadiness works out the standing of a budget in a month, given all
+// of its transactions, the money assigned in the month, and the balance
+// of all categories at its end. Income is every inflow of the month which
+// is not a transfer, in the home currency as of the day it came in.
+func monthReadiness(txns []*pgo.TransactionDetail, month time.Time, currencies map[string]string, home string,
+	convert homeConverter, assigned, categoryBalance cc.Money,
+) (readiness, error) {
+	worth, missing, err := netWorthByMonth(txns, []time.Time{month}, currencies, home, convert)
+	if err != nil {
+		return readiness{}, err
+	}
+	ready, err := worth[0].Sub(categoryBalance)
+	if err != nil {
+		return readiness{}, err
+	}
+
+	income := cc.NewMoney(0, home)
+	for _, txn := range txns {
+		inMonth := txn.TransactionDate.Format(dates.MonthLayout) == month.Format(dates.MonthLayout)
+		if !inMonth || txn.TransferAccountName != "" || txn.TotalAmount <= 0 {
+			continue
+		}
+		iso, ok := currencies[txn.AccountName]
+		if !ok {
+			iso = home
+		}
+		converted, found, err := convert(cc.NewMoney(txn.TotalAmount, iso), txn.TransactionDate)
+		if err != nil {
+			return readiness{}, err
+		}
+		if !found {
+			if !slices.Contains(missing, iso) {
+				missing = append(missing, iso)
+				slices.Sort(missing)
+			}
+			continue
+		}
+		if income, err = income.Add(converted); err != nil {
+			return readiness{}, fmt.Errorf("could not total income: %w", err)
+		}
+	}
+	return readiness{income: income, assigned: assigned, ready: ready, missing: missing}, nil
+}
+
+// printReadiness prints the standing of a budget in a month,
+// warning if more was assigned than there is money for.
+func (s *State) printReadiness(r readiness) {
+	fmt.Printf("  %-16s %s\n", "Income:", r.income)
+	fmt.Printf("  %-16s %s\n", "Assigned:", r.assigned)
+	fmt.Printf("  %-16s %s\n", "Ready to assign:", r.ready)
+	if len(r.missing) > 0 {
+		fmt.Printf("  Excludes accounts kept in %v, having no rate to %s\n", r.missing, r.ready.ISOCode)
+	}
+	if r.ready.IsNegative() {
+		over, err := r.ready.Neg()
+		if err != nil {
+			over = r.ready
+		}
+		warning := fmt.Sprintf("Overassigned by %s; assign less, or move money out of categories.", over)
+		fmt.Println("  " + s.paint(red)(warning))
+	}
+}
+
+// promptReadiness is the money left to assign shown in the prompt,
+// as worked out for a budget in a month. A failure to work it out is
+// kept as well, so that it is not retried each time the prompt is drawn.
+type promptReadiness struct {
+	budgetID string
+	month    string
+	ready    cc.Money
+	ok       bool
+}
+
+// readOnlyCommands name the commands, and the actions of the others,
+// which cannot change the money left to assign. A nil list of actions
+// covers the whole of a command.
+var readOnlyCommands = map[string][]string{
+	"help":     nil,
+	"clear":    nil,
+	"ready":    nil,
+	"alias":    nil,
+	"unalias":  nil,
+	"rate":     {"list"},
+	"budget":   {"report", "list"},
+	"undo":     {"list"},
+	"account":  {"list"},
+	"category": {"reports", "list"},
+	"group":    {"list"},
+	"payee":    {"list"},
+	"txn":      {"list"},
+	"report":   {"chart"},
+	"goal":     {"status"},
+}
+
+// leavesReadiness reports whether a command run with the given
+// action leaves the money left to assign as it was.
+func leavesReadiness(cmdName, action string) bool {
+	actions, found := readOnlyCommands[cmdName]
+	return found && (actions == nil || slices.Contains(actions, action))
+}
+
+// promptReadyToAssign returns the money left to assign this month in
+// the budget in view, for the prompt, if it is configured to be shown.
+// It is worked out once for each budget and month, until a command
+// changes it. Any failure leaves it out, rather than getting in the
+// way of the prompt.
+func (s *State) promptReadyToAssign() (cc.Money, bool) {
+	if s.Config == nil || !s.Config.PromptReadyToAssign || s.Session.ActiveBudget.Name == "" {
+		return cc.Money{}, false
+	}
+	p, err := s.dateParser()
+	if err != nil {
+		slog.Warn("could not show ready to assign in prompt", slog.Any("error", err))
+		return cc.Money{}, false
+	}
+	month, err := p.Month("this month")
+	if err != nil {
+		slog.Warn("could not show ready to assign in prompt", slog.Any("error", err))
+		return cc.Money{}, false
+	}
+
+	kept := &promptReadiness{
+		budgetID: s.Session.ActiveBudget.ID.String(),
+		month:    month.Format(dates.MonthLayout),
+	}
+	if last := s.Session.promptReady; last != nil && last.budgetID == kept.budgetID && last.month == kept.month {
+		return last.ready, last.ok
+	}
+	r, err := s.readyInMonth(month)
+	if err != nil {
+		slog.Warn("could not show ready to assign in prompt", slog.Any("error", err))
+	} else {
+		kept.ready, kept.ok = r.ready, true
+	}
+	s.Session.promptReady = kept
+	return kept.ready, kept.ok
+}
+
+// readyInMonth works out the standing of the budget in view in a month.
+func (s *State) readyInMonth(month time.Time) (readiness, error) {
+	report, err := s.Client.BudgetReport(s.Session.ActiveBudget.ID.String(), month.Format(dates.DayLayout))
+	if err != nil {
+		return readiness{}, err
+	}
+	return s.readyToAssign(month, report)
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YouWantToPinch/pincher-cli/internal/config"
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestMonthReadiness(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	txns := []*pgo.TransactionDetail{
+		{AccountName: "Checking", TransactionDate: day(time.September, 30), TotalAmount: 100000},
+		{AccountName: "Checking", TransactionDate: day(time.October, 1), TotalAmount: 250000},
+		{AccountName: "Checking", TransactionDate: day(time.October, 3), TotalAmount: -40000},
+		{AccountName: "Checking", TransactionDate: day(time.October, 5), TotalAmount: -50000, TransferAccountName: "Savings"},
+		{AccountName: "Savings", TransactionDate: day(time.October, 5), TotalAmount: 50000, TransferAccountName: "Checking"},
+		{AccountName: "Yen Wallet", TransactionDate: day(time.October, 9), TotalAmount: 1000},
+		{AccountName: "Checking", TransactionDate: day(time.November, 1), TotalAmount: 250000},
+	}
+	currencies := map[string]string{"Checking": "USD", "Savings": "USD", "Yen Wallet": "JPY"}
+	convert := func(amount cc.Money, on time.Time) (cc.Money, bool, error) {
+		return amount, amount.ISOCode == "USD", nil
+	}
+	month := day(time.October, 1)
+
+	tests := []struct {
+		name            string
+		assigned        int64
+		categoryBalance int64
+		expectedReady   cc.Money
+	}{
+		{
+			name:            "money left over",
+			assigned:        200000,
+			categoryBalance: 250000,
+			expectedReady:   cc.NewMoney(60000, "USD"),
+		},
+		{
+			name:            "overassigned",
+			assigned:        300000,
+			categoryBalance: 330000,
+			expectedReady:   cc.NewMoney(-20000, "USD"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := monthReadiness(txns, month, currencies, "USD", convert,
+				cc.NewMoney(tt.assigned, "USD"), cc.NewMoney(tt.categoryBalance, "USD"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.income.Equal(cc.NewMoney(250000, "USD")) {
+				t.Errorf("expected income of $2,500.00, got %v", r.income)
+			}
+			if !r.assigned.Equal(cc.NewMoney(tt.assigned, "USD")) {
+				t.Errorf("expected assigned %v, got %v", tt.assigned, r.assigned)
+			}
+			if !r.ready.Equal(tt.expectedReady) {
+				t.Errorf("expected ready to assign %v, got %v", tt.expectedReady, r.ready)
+			}
+			if !slices.Equal(r.missing, []string{"JPY"}) {
+				t.Errorf("expected missing currencies [JPY], got %v", r.missing)
+			}
+		})
+	}
+}
+
+func TestPromptReadyToAssignIsKept(t *testing.T) {
+	s := &State{
+		Config:  &config.Config{ConfigSettings: config.ConfigSettings{PromptReadyToAssign: true}},
+		Session: &cliSession{ActiveBudget: pgo.Budget{MetaData: pgo.MetaData{Name: "Home"}}},
+	}
+	s.Session.promptReady = &promptReadiness{
+		budgetID: s.Session.ActiveBudget.ID.String(),
+		month:    time.Now().Format(dates.MonthLayout),
+		ready:    cc.NewMoney(1500, "USD"),
+		ok:       true,
+	}
+
+	// without a client, only the money kept from before may be shown
+	ready, ok := s.promptReadyToAssign()
+	if !ok || ready.Amount != 1500 {
+		t.Errorf("expected the kept $15.00 to be shown, but got %v (shown: %v)", ready, ok)
+	}
+}
+
+func TestLeavesReadiness(t *testing.T) {
+	tests := []struct {
+		cmdName  string
+		action   string
+		expected bool
+	}{
+		{cmdName: "help", expected: true},
+		{cmdName: "txn", action: "list", expected: true},
+		{cmdName: "budget", action: "report", expected: true},
+		{cmdName: "txn", action: "log", expected: false},
+		{cmdName: "budget", action: "view", expected: false},
+		{cmdName: "undo", expected: false},
+		{cmdName: "config", action: "set", expected: false},
+	}
+
+	for _, tt := range tests {
+		if actual := leavesReadiness(tt.cmdName, tt.action); actual != tt.expected {
+			t.Errorf("%s %s: expected %v, but got %v", tt.cmdName, tt.action, tt.expected, actual)
+		}
+	}
+}
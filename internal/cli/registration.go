@@ -1,5 +1,25 @@
 package cli
 
+// makeGlobalOptions describes the options which may accompany
+// any command, as parsed by parseGlobalOptions.
+func makeGlobalOptions() []cmdElement {
+	return []cmdElement{
+		{
+			name:        "output",
+			description: "Format in which to report errors. Can be text (default) or json.",
+			parameters:  []string{"format"},
+		},
+		{
+			name:        "yes",
+			description: "Skip confirmation of destructive actions, such as deletions. Required to run them when input is not a terminal.",
+		},
+		{
+			name:        "dry-run",
+			description: "Parse and validate a command that would change data, printing the request it would make instead of making it.",
+		},
+	}
+}
+
 func makeBaseCommandHandlers() []*cmdHandler {
 	mdAct := middlewareValidateAction
 
@@ -58,9 +78,95 @@ func makeBaseCommandHandlers() []*cmdHandler {
 					name:        "load",
 					description: "load user configuration from the local machine",
 				},
+				{
+					name:        "set",
+					description: "change a single setting of the user configuration; settings include: currency, stay-logged-in, vim-keys",
+					parameters:  []string{"setting", "value"},
+				},
 			},
 			callback: mdAct(handlerConfig),
 		},
+		{
+			cmdElement: cmdElement{
+				name:        "alias",
+				description: "Define a shortcut for a command, as in: alias coffee = txn log Checking \"Blue Bottle\" $1 \"Dining Out\". Placeholders like $1 are replaced by arguments given to the shortcut. Without arguments, lists all shortcuts.",
+				parameters:  []string{"name", "command..."},
+				priority:    15,
+				variadic:    true,
+			},
+			callback: handlerAlias,
+		},
+		{
+			cmdElement: cmdElement{
+				name:        "unalias",
+				description: "Remove a shortcut defined with 'alias'",
+				parameters:  []string{"name"},
+				priority:    16,
+			},
+			callback: handlerUnalias,
+		},
+		{
+			cmdElement: cmdElement{
+				name:        "rate",
+				description: "Manage the local table of exchange rates used to convert balances of accounts kept in other currencies",
+				parameters:  []string{"action"},
+				priority:    17,
+			},
+			callback: mdAct(handlerRate),
+			actions: []cmdElement{
+				{
+					name:        "list",
+					aliases:     []string{"ls"},
+					description: "see all exchange rates, oldest first",
+					options: []cmdElement{
+						{
+							name:         "from",
+							description:  "only show rates involving this currency",
+							parameters:   []string{"ISO_code"},
+							useShorthand: true,
+						},
+						{
+							name:         "to",
+							description:  "only show rates between the --from currency and this one",
+							parameters:   []string{"ISO_code"},
+							useShorthand: true,
+						},
+					},
+				},
+				{
+					name:        "set",
+					description: "set the value of one unit of a currency in another, as in: rate set EUR USD 1.0845",
+					parameters:  []string{"from", "to", "rate"},
+					options: []cmdElement{
+						{
+							name:         "date",
+							description:  "the date from which the rate applies (defaults to present day). Also accepts relative dates, as in yesterday, -3d or last friday",
+							parameters:   []string{"date"},
+							useShorthand: true,
+						},
+					},
+				},
+				{
+					name:        "delete",
+					aliases:     []string{"rm"},
+					description: "delete the rate between two currencies set on a date",
+					parameters:  []string{"from", "to"},
+					options: []cmdElement{
+						{
+							name:         "date",
+							description:  "the date of the rate (defaults to present day). Also accepts relative dates, as in yesterday, -3d or last friday",
+							parameters:   []string{"date"},
+							useShorthand: true,
+						},
+					},
+				},
+				{
+					name:        "import",
+					description: "import historical rates from a CSV file with lines of the form: date,from,to,rate",
+					parameters:  []string{"path"},
+				},
+			},
+		},
 		{
 			cmdElement: cmdElement{
 				name:        "ready",
@@ -120,6 +226,7 @@ func makeBaseCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "delete",
+					aliases:     []string{"rm"},
 					description: "delete the logged-in user by first entering its credentials",
 					parameters:  []string{"username", "password", "retype_password"},
 				},
@@ -150,6 +257,11 @@ func makeBudgetCommandHandler() *cmdHandler {
 						description: "Give your budget some notes",
 						parameters:  []string{"notes_value"},
 					},
+					{
+						name:        "currency",
+						description: "ISO 4217 code of the currency the budget is kept in; defaults to the configured currency",
+						parameters:  []string{"ISO_code"},
+					},
 				},
 			},
 			{
@@ -159,14 +271,34 @@ func makeBudgetCommandHandler() *cmdHandler {
 				options: []cmdElement{
 					{
 						name:         "month",
-						description:  "Specify a month within which to pull a report. You will see a reflection of the budget at that point in time.",
+						description:  "Specify a month within which to pull a report. You will see a reflection of the budget at that point in time. Accepts YYYY-MM, as well as oct, oct 2025, this month or last month.",
 						useShorthand: true,
 						parameters:   []string{"YYYY-MM"},
 					},
+					{
+						name:        "from",
+						description: "Report on each month from this one to --to (defaults to this month) side by side, in place of --month",
+						parameters:  []string{"YYYY-MM"},
+					},
+					{
+						name:        "to",
+						description: "The last month to report on along with --from",
+						parameters:  []string{"YYYY-MM"},
+					},
+					{
+						name:        "show",
+						description: "The figure to show of each category by month with --from: assigned, activity (default) or balance",
+						parameters:  []string{"figure"},
+					},
+					{
+						name:        "deltas",
+						description: "Follow each figure with its change since the month before, with --from",
+					},
 				},
 			},
 			{
-				name: "list",
+				name:    "list",
+				aliases: []string{"ls"},
 				options: []cmdElement{
 					{
 						name:        "roles",
@@ -195,13 +327,87 @@ func makeBudgetCommandHandler() *cmdHandler {
 						description: "Update budget name",
 						parameters:  []string{"notes_value"},
 					},
+					{
+						name:        "currency",
+						description: "Update the currency the budget is kept in, stored on this machine",
+						parameters:  []string{"ISO_code"},
+					},
 				},
 			},
 			{
 				name:        "delete",
+				aliases:     []string{"rm"},
 				description: "delete an existing budget by name",
 				parameters:  []string{"budget_name"},
 			},
+			{
+				name:        "autoassign",
+				description: "assign each category of the budget in view by a strategy: copy, what was assigned last month; activity, what was spent last month; average, what was spent in an average month; or template, a saved template. Shows the changes to be made before making them",
+				parameters:  []string{"strategy"},
+				options: []cmdElement{
+					{
+						name:         "month",
+						description:  "the month to assign in (defaults to this month). Accepts YYYY-MM, as well as oct, oct 2025, this month or next month.",
+						useShorthand: true,
+						parameters:   []string{"YYYY-MM"},
+					},
+					{
+						name:        "months",
+						description: "the number of months before to average with the average strategy (defaults to 3)",
+						parameters:  []string{"count"},
+					},
+					{
+						name:         "template",
+						description:  "the name of the template to assign with the template strategy",
+						useShorthand: true,
+						parameters:   []string{"name"},
+					},
+					{
+						name:        "save-template",
+						description: "save the amounts planned by the strategy as a template of this name, in place of assigning them",
+						parameters:  []string{"name"},
+					},
+				},
+			},
+			{
+				name:        "cover",
+				description: "cover each category overspent in a month by moving money from categories with some to spare, choosing where from for each, or letting the CLI choose with --auto",
+				options: []cmdElement{
+					{
+						name:         "month",
+						description:  "the month to cover overspending in (defaults to this month). Accepts YYYY-MM, as well as oct, oct 2025, this month or last month.",
+						useShorthand: true,
+						parameters:   []string{"YYYY-MM"},
+					},
+					{
+						name:         "auto",
+						description:  "cover each overspent category from those with the most to spare, without asking",
+						useShorthand: true,
+					},
+				},
+			},
+		},
+	}
+
+	return handler
+}
+
+func makeUndoCommandHandler() *cmdHandler {
+	handler := &cmdHandler{
+		cmdElement: cmdElement{
+			name:        "undo",
+			description: "Undo the most recent change made during this session. Deletions which cannot be restored are not undoable.",
+			parameters:  []string{"action"},
+			priority:    90,
+		},
+		nonRegMsg: "login required",
+		callback:  handlerUndo,
+		actions: []cmdElement{
+			{
+				name:        "list",
+				aliases:     []string{"ls"},
+				description: "list changes which may be undone, most recent first",
+			},
 		},
 	}
 
@@ -237,6 +443,11 @@ func makeResourceCommandHandlers() []*cmdHandler {
 							description:  "create the account for tracking purposes only, seperating it from any categorization",
 							useShorthand: true,
 						},
+						{
+							name:        "currency",
+							description: "the currency the account is kept in, if not that of the budget (stored on this machine only)",
+							parameters:  []string{"ISO_code"},
+						},
 					},
 				},
 				{
@@ -259,6 +470,11 @@ func makeResourceCommandHandlers() []*cmdHandler {
 							description: "choose different account type",
 							parameters:  []string{"new_type"},
 						},
+						{
+							name:        "currency",
+							description: "change the currency the account is kept in (stored on this machine only), or 'budget' to keep it in that of its budget",
+							parameters:  []string{"ISO_code"},
+						},
 					},
 				},
 				{
@@ -268,6 +484,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "list",
+					aliases:     []string{"ls"},
 					description: "see a list of all accounts belonging to budget",
 					options: []cmdElement{
 						{
@@ -275,10 +492,16 @@ func makeResourceCommandHandlers() []*cmdHandler {
 							description:  "view only soft-deleted accounts",
 							useShorthand: true,
 						},
+						{
+							name:         "balances",
+							description:  "show the balance of each account, converting those kept in other currencies with the latest exchange rate",
+							useShorthand: true,
+						},
 					},
 				},
 				{
 					name:        "delete",
+					aliases:     []string{"rm"},
 					description: "Delete an account",
 					parameters:  []string{"account_name"},
 					options: []cmdElement{
@@ -321,7 +544,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "assign",
-					description: "assign an amount of money to a category by name",
+					description: "assign an amount of money to a category by name. The amount may be an arithmetic expression, as in 45*4",
 					parameters:  []string{"category_name", "amount"},
 					options: []cmdElement{
 						{
@@ -330,9 +553,14 @@ func makeResourceCommandHandlers() []*cmdHandler {
 							useShorthand: true,
 							parameters:   []string{"from_category"},
 						},
+						{
+							name:        "fund-goals",
+							description: "In place of a category and amount, assign each category with a goal exactly what it still needs this month, from the money ready to assign",
+							standsIn:    true,
+						},
 						{
 							name:         "month",
-							description:  "Specify a month within which to make the assignent. This affects future balances relative to that point in time.",
+							description:  "Specify a month within which to make the assignent. This affects future balances relative to that point in time. Accepts YYYY-MM, as well as oct, oct 2025, this month or last month.",
 							useShorthand: true,
 							parameters:   []string{"YYYY-MM"},
 						},
@@ -340,14 +568,33 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "reports",
-					description: "get a reports for all categories",
+					description: "get reports for all categories, by group",
 					options: []cmdElement{
 						{
 							name:         "month",
-							description:  "Specify a month within which to pull reports. You will see a reflection of the categories at that point in time.",
+							description:  "Specify a month within which to pull reports. You will see a reflection of the categories at that point in time. Accepts YYYY-MM, as well as oct, oct 2025, this month or last month.",
 							useShorthand: true,
 							parameters:   []string{"YYYY-MM"},
 						},
+						{
+							name:        "from",
+							description: "Report on each month from this one to --to (defaults to this month) side by side, in place of --month",
+							parameters:  []string{"YYYY-MM"},
+						},
+						{
+							name:        "to",
+							description: "The last month to report on along with --from",
+							parameters:  []string{"YYYY-MM"},
+						},
+						{
+							name:        "show",
+							description: "The figure to show of each category by month with --from: assigned, activity (default) or balance",
+							parameters:  []string{"figure"},
+						},
+						{
+							name:        "deltas",
+							description: "Follow each figure with its change since the month before, with --from",
+						},
 					},
 				},
 				{
@@ -375,6 +622,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "list",
+					aliases:     []string{"ls"},
 					description: "list all categories belonging to budget",
 					options: []cmdElement{
 						{
@@ -387,6 +635,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "delete",
+					aliases:     []string{"rm"},
 					description: "Delete a category",
 					parameters:  []string{"category_name"},
 				},
@@ -433,10 +682,12 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "list",
+					aliases:     []string{"ls"},
 					description: "see a list of all groups belonging to budget",
 				},
 				{
 					name:        "delete",
+					aliases:     []string{"rm"},
 					description: "Delete a group",
 					parameters:  []string{"group_name"},
 				},
@@ -483,10 +734,12 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "list",
+					aliases:     []string{"ls"},
 					description: "see a list of all payees belonging to budget",
 				},
 				{
 					name:        "delete",
+					aliases:     []string{"rm"},
 					description: "Delete a payee",
 					parameters:  []string{"payee_name"},
 					options: []cmdElement{
@@ -503,6 +756,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 		{
 			cmdElement: cmdElement{
 				name:        "txn",
+				aliases:     []string{"transaction"},
 				parameters:  []string{"action"},
 				description: "Manage category groups under budget in view",
 				priority:    230,
@@ -512,6 +766,7 @@ func makeResourceCommandHandlers() []*cmdHandler {
 			actions: []cmdElement{
 				{
 					name:        "list",
+					aliases:     []string{"ls"},
 					description: "see a list of transactions",
 					options: []cmdElement{
 						{
@@ -534,20 +789,81 @@ func makeResourceCommandHandlers() []*cmdHandler {
 						},
 						{
 							name:         "dates",
-							description:  "filter by time frame",
+							description:  "filter by time frame, including both ends. Dates may be relative, as in: --dates -1m today",
 							parameters:   []string{"start_date", "end_date"},
 							useShorthand: true,
 						},
+						{
+							name:        "min",
+							description: "filter out transactions smaller than this amount, whether inflows or outflows",
+							parameters:  []string{"amount"},
+						},
+						{
+							name:        "max",
+							description: "filter out transactions larger than this amount, whether inflows or outflows",
+							parameters:  []string{"amount"},
+						},
+						{
+							name:        "cleared",
+							description: "only show cleared transactions",
+						},
+						{
+							name:        "uncleared",
+							description: "only show transactions not yet cleared",
+						},
+						{
+							name:         "notes",
+							description:  "only show transactions with notes containing this text, in any case",
+							parameters:   []string{"text"},
+							useShorthand: true,
+						},
+						{
+							name:        "transfers",
+							description: "only show transfers between accounts",
+						},
+						{
+							name:         "group",
+							description:  "only show transactions with a category in this group",
+							parameters:   []string{"group_name"},
+							useShorthand: true,
+						},
+						{
+							name:         "sort",
+							description:  "sort by date, amount or payee (defaults to date)",
+							parameters:   []string{"field"},
+							useShorthand: true,
+						},
+						{
+							name:         "reverse",
+							description:  "reverse the order of transactions, as in showing the latest first",
+							useShorthand: true,
+						},
+						{
+							name:         "limit",
+							description:  "show no more than this many transactions, after sorting",
+							parameters:   []string{"count"},
+							useShorthand: true,
+						},
+						{
+							name:        "columns",
+							description: "choose the columns to show, in order, from: date, account, payee, category, amount, cleared, balance, notes, id. The balance column shows the running balance of each account",
+							parameters:  []string{"column,..."},
+						},
+						{
+							name:         "expand-splits",
+							description:  "show each split of a split transaction beneath it",
+							useShorthand: true,
+						},
 					},
 				},
 				{
 					name:        "log",
-					description: "log a deposit or withdrawal transaction to the budget in view.",
+					description: "log a deposit or withdrawal transaction to the budget in view. Amounts may be arithmetic expressions, as in =84.20/3+5",
 					parameters:  []string{"account", "payee", "amount", "category"},
 					options: []cmdElement{
 						{
 							name:         "date",
-							description:  "specify a date date for this transaction (defaults to present day)",
+							description:  "specify a date date for this transaction (defaults to present day). Also accepts relative dates, as in yesterday, -3d or last friday",
 							parameters:   []string{"date"},
 							useShorthand: true,
 						},
@@ -572,12 +888,13 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 				{
 					name:        "transfer",
-					description: "log a transfer transaction between two accounts within the budget in view",
+					aliases:     []string{"tfr"},
+					description: "log a transfer transaction between two accounts within the budget in view. The amount may be an arithmetic expression",
 					parameters:  []string{"from_account", "to_account", "amount"},
 					options: []cmdElement{
 						{
 							name:         "date",
-							description:  "specify a date date for this transfer (defaults to present day)",
+							description:  "specify a date date for this transfer (defaults to present day). Also accepts relative dates, as in yesterday, -3d or last friday",
 							parameters:   []string{"date"},
 							useShorthand: true,
 						},
@@ -596,6 +913,84 @@ func makeResourceCommandHandlers() []*cmdHandler {
 				},
 			},
 		},
+		{
+			cmdElement: cmdElement{
+				name:        "report",
+				parameters:  []string{"action"},
+				description: "Draw charts of the budget in view",
+				priority:    250,
+			},
+			nonRegMsg: "first view a budget to report on it",
+			callback:  mdAct(handlerReport),
+			actions: []cmdElement{
+				{
+					name:        "chart",
+					description: "draw a chart of: spending, the spending of each category in a month as bars; activity, the spending of each category by month as sparklines; or networth, the sum of all accounts by month as a line",
+					parameters:  []string{"kind"},
+					options: []cmdElement{
+						{
+							name:         "month",
+							description:  "the month of a spending chart (defaults to this month)",
+							useShorthand: true,
+							parameters:   []string{"YYYY-MM"},
+						},
+						{
+							name:        "from",
+							description: "the first month of an activity or networth chart (defaults to the last 6 months for activity, or 12 for networth)",
+							parameters:  []string{"YYYY-MM"},
+						},
+						{
+							name:        "to",
+							description: "the last month of an activity or networth chart (defaults to this month)",
+							parameters:  []string{"YYYY-MM"},
+						},
+					},
+				},
+			},
+		},
+		{
+			cmdElement: cmdElement{
+				name:        "goal",
+				parameters:  []string{"action"},
+				description: "Set goals for the categories of the budget in view, and track progress toward them",
+				priority:    225,
+			},
+			nonRegMsg: "first view a budget to set goals for its categories",
+			callback:  mdAct(handlerGoal),
+			actions: []cmdElement{
+				{
+					name:        "set",
+					description: "set the goal of a category: monthly, an amount to assign every month; target, a balance to reach by a month; or cap, the most to spend in a month. Amounts may be arithmetic expressions",
+					parameters:  []string{"category_name", "kind", "amount"},
+					options: []cmdElement{
+						{
+							name:         "by",
+							description:  "the month by which a target is to be reached, as in 2027-06 or dec",
+							useShorthand: true,
+							parameters:   []string{"YYYY-MM"},
+						},
+					},
+				},
+				{
+					name:        "delete",
+					aliases:     []string{"rm"},
+					description: "delete the goal of a category",
+					parameters:  []string{"category_name"},
+				},
+				{
+					name:        "status",
+					description: "see the progress toward each goal, the money each still needs, and which are underfunded",
+					options: []cmdElement{
+						{
+							name:         "month",
+							description:  "the month to see progress in (defaults to this month). Accepts YYYY-MM, as well as oct, oct 2025, this month or last month.",
+							useShorthand: true,
+							parameters:   []string{"YYYY-MM"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	return handlers
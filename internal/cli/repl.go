@@ -8,11 +8,9 @@ import (
 	"os"
 )
 
-func StartRepl(cliState *State) {
-	if cliState == nil {
-		panic("StartRepl: cliState is nil")
-	}
-
+// startSession readies the CLI state to take commands,
+// simulating a login if a session was saved.
+func startSession(cliState *State) chan string {
 	cliState.NewSession()
 
 	commandQueue := make(chan string, 32)
@@ -29,10 +27,40 @@ func StartRepl(cliState *State) {
 		}
 	}
 
+	return commandQueue
+}
+
+// runCommand expands any alias in the given command fields and
+// separates global options from them before running the command,
+// reporting any resulting error.
+func (s *State) runCommand(cmdFields []string) error {
+	globals := globalOptions{}
+	cmdFields, err := s.expandAlias(cmdFields)
+	if err == nil {
+		globals, cmdFields, err = parseGlobalOptions(cmdFields)
+	}
+	if err == nil {
+		err = s.Session.CommandRegistry.run(s, globals, cmdFields)
+	}
+	if err != nil {
+		slog.Error(err.Error())
+		printError(err, globals.output)
+	}
+	return err
+}
+
+func StartRepl(cliState *State) {
+	if cliState == nil {
+		panic("StartRepl: cliState is nil")
+	}
+
+	commandQueue := startSession(cliState)
+
 	cliState.styles = &styles{}
 	cliState.styles.Init()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	cliState.scanner = bufio.NewScanner(os.Stdin)
+	scanner := cliState.scanner
 	fmt.Println("Welcome to the Pincher CLI!")
 	fmt.Println("Use 'help' for available commands.")
 	for {
@@ -55,11 +83,27 @@ func StartRepl(cliState *State) {
 				*cliState.DoneChan <- true
 				return
 			}
-			err := cliState.Session.CommandRegistry.run(cliState, cmd)
-			if err != nil {
-				slog.Error(err.Error())
-				fmt.Println("ERROR:", err)
-			}
+			_ = cliState.runCommand(cleanInput(cmd))
 		}
 	}
 }
+
+// RunOnce runs a single command given as process arguments,
+// along with any commands it queues, outside of the REPL.
+// It returns the exit code that the process should exit with.
+func RunOnce(cliState *State, cmdFields []string) int {
+	if cliState == nil {
+		panic("RunOnce: cliState is nil")
+	}
+
+	commandQueue := startSession(cliState)
+
+	if len(cmdFields) == 0 || cmdFields[0] == "exit" {
+		return ExitOK
+	}
+	err := cliState.runCommand(cmdFields)
+	for err == nil && len(commandQueue) > 0 {
+		err = cliState.runCommand(cleanInput(<-commandQueue))
+	}
+	return ExitCode(err)
+}
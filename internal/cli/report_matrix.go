@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// maxReportMonths is the most months that a single report may span.
+const maxReportMonths = 36
+
+// reportFields are the figures of a category report
+// which a report spanning several months may show.
+var reportFields = map[string]func(r *pgo.CategoryReport) int64{
+	"assigned": func(r *pgo.CategoryReport) int64 { return r.Assigned },
+	"activity": func(r *pgo.CategoryReport) int64 { return r.Activity },
+	"balance":  func(r *pgo.CategoryReport) int64 { return r.Balance },
+}
+
+// reportSection is a group of categories as shown in a report.
+type reportSection struct {
+	name       string
+	categories []string
+}
+
+// monthRange reads the --from and --to options of a report, returning
+// the first day of each month from one to the other. It reports false
+// if neither option was given.
+func (s *State) monthRange(c *handlerContext) ([]time.Time, bool, error) {
+	c.args.trackOptArgs(&c.cmd, "from")
+	from, _ := c.args.pfx()
+	c.args.trackOptArgs(&c.cmd, "to")
+	to, _ := c.args.pfx()
+	if from == "" && to == "" {
+		return nil, false, nil
+	}
+	if from == "" {
+		return nil, false, newCLIError(errKindUsage, "--to must be given along with --from")
+	}
+	p, err := s.dateParser()
+	if err != nil {
+		return nil, false, err
+	}
+	first, err := p.Month(from)
+	if err != nil {
+		return nil, false, newCLIError(errKindUsage, "%v", err)
+	}
+	if to == "" {
+		to = "this month"
+	}
+	last, err := p.Month(to)
+	if err != nil {
+		return nil, false, newCLIError(errKindUsage, "%v", err)
+	}
+	if last.Before(first) {
+		return nil, false, newCLIError(errKindUsage, "--to month %s comes before --from month %s", last.Format(dates.MonthLayout), first.Format(dates.MonthLayout))
+	}
+
+	months := []time.Time{}
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		if len(months) == maxReportMonths {
+			return nil, false, newCLIError(errKindUsage, "a report may span no more than %d months", maxReportMonths)
+		}
+		months = append(months, month)
+	}
+	return months, true, nil
+}
+
+// fetchCategoryReports retrieves the category reports of
+// the budget in view for each of the given months.
+func (s *State) fetchCategoryReports(months []time.Time) ([][]*pgo.CategoryReport, error) {
+	bID := s.Session.ActiveBudget.ID.String()
+	reports := make([][]*pgo.CategoryReport, len(months))
+	for i, month := range months {
+		var err error
+		reports[i], err = s.Client.BudgetCategoryReports(bID, month.Format(dates.DayLayout))
+		if err != nil {
+			return nil, fmt.Errorf("could not get reports for %s: %w", month.Format(dates.MonthLayout), err)
+		}
+	}
+	return reports, nil
+}
+
+// reportSections arranges the given categories into sections by group,
+// in order of name, followed by any categories belonging to no group.
+func (s *State) reportSections(categories []string) ([]reportSection, error) {
+	bID := s.Session.ActiveBudget.ID.String()
+	groups, err := s.GetGroups(bID, "")
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.GetCategories(bID, "")
+	if err != nil {
+		return nil, err
+	}
+	return sectionsByGroup(categories, groups, all), nil
+}
+
+// sectionsByGroup arranges the named categories into sections by the
+// group of each among all categories, in order of group name, followed
+// by any categories belonging to no group.
+func sectionsByGroup(categories []string, groups []*pgo.Group, all []*pgo.Category) []reportSection {
+	members := map[string][]string{}
+	for _, category := range all {
+		if category.GroupID != nil && slices.Contains(categories, category.Name) {
+			members[category.GroupID.String()] = append(members[category.GroupID.String()], category.Name)
+		}
+	}
+	groups = slices.Clone(groups)
+	slices.SortFunc(groups, func(a, b *pgo.Group) int { return cmp.Compare(a.Name, b.Name) })
+
+	grouped := map[string]bool{}
+	sections := []reportSection{}
+	for _, group := range groups {
+		section := reportSection{name: group.Name, categories: members[group.ID.String()]}
+		for _, member := range section.categories {
+			grouped[member] = true
+		}
+		if len(section.categories) > 0 {
+			slices.Sort(section.categories)
+			sections = append(sections, section)
+		}
+	}
+
+	ungrouped := reportSection{name: "Ungrouped"}
+	for _, category := range categories {
+		if !grouped[category] {
+			ungrouped.categories = append(ungrouped.categories, category)
+		}
+	}
+	if len(ungrouped.categories) > 0 {
+		slices.Sort(ungrouped.categories)
+		sections = append(sections, ungrouped)
+	}
+	return sections
+}
+
+// reportMatrix lays out a figure of each category by month, with a
+// subtotal for each section of more than one and a total for each month.
+// Figures which add up over time, unlike balances, are also totalled
+// for each category. With deltas, each figure after the first month
+// is followed by its change since the month before.
+func reportMatrix(months []time.Time, reports [][]*pgo.CategoryReport, sections []reportSection, field string, deltas bool, ISOCode string) ([]string, [][]string, error) {
+	zeroes := func() []cc.Money {
+		sums := make([]cc.Money, len(months))
+		for i := range sums {
+			sums[i] = cc.NewMoney(0, ISOCode)
+		}
+		return sums
+	}
+	figureOf := reportFields[field]
+	figures := map[string][]cc.Money{}
+	for i, monthReports := range reports {
+		for _, report := range monthReports {
+			if _, ok := figures[report.Name]; !ok {
+				figures[report.Name] = zeroes()
+			}
+			figures[report.Name][i] = cc.NewMoney(figureOf(report), ISOCode)
+		}
+	}
+	totalled := field != "balance"
+
+	headers := []string{"CATEGORY"}
+	for _, month := range months {
+		headers = append(headers, month.Format(dates.MonthLayout))
+	}
+	if totalled {
+		headers = append(headers, "TOTAL")
+	}
+
+	formatRow := func(label string, values []cc.Money) ([]string, error) {
+		row := []string{label}
+		total := cc.NewMoney(0, ISOCode)
+		for i, value := range values {
+			cell := value.String()
+			if deltas && i > 0 {
+				delta, err := value.Sub(values[i-1])
+				if err != nil {
+					return nil, err
+				}
+				cell += " (" + signed(delta) + ")"
+			}
+			row = append(row, cell)
+			var err error
+			if total, err = total.Add(value); err != nil {
+				return nil, err
+			}
+		}
+		if totalled {
+			row = append(row, total.String())
+		}
+		return row, nil
+	}
+	addInto := func(sums []cc.Money, values []cc.Money) error {
+		for i := range sums {
+			var err error
+			if sums[i], err = sums[i].Add(values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rows := [][]string{}
+	totals := zeroes()
+	// a lone section is the whole report, and needs no heading
+	showSections := len(sections) > 1
+	for _, section := range sections {
+		indent := ""
+		if showSections {
+			rows = append(rows, []string{section.name})
+			indent = "  "
+		}
+		subtotals := zeroes()
+		for _, category := range section.categories {
+			values, ok := figures[category]
+			if !ok {
+				values = zeroes()
+			}
+			row, err := formatRow(indent+category, values)
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, row)
+			if err := addInto(subtotals, values); err != nil {
+				return nil, nil, err
+			}
+		}
+		if showSections && len(section.categories) > 1 {
+			row, err := formatRow(indent+"Total "+section.name, subtotals)
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, row)
+		}
+		if err := addInto(totals, subtotals); err != nil {
+			return nil, nil, err
+		}
+	}
+	row, err := formatRow("TOTAL", totals)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows = append(rows, row)
+	return headers, rows, nil
+}
+
+// categoryTree lays out the figures of each category in a month beneath
+// the heading of its section, followed by a subtotal of the section and
+// a total of all. Balances below zero, having been overspent, are
+// painted as such, being in the last column.
+func categoryTree(reports []*pgo.CategoryReport, sections []reportSection, ISOCode string, paintOverspent func(string) string) ([][]string, error) {
+	byName := map[string]*pgo.CategoryReport{}
+	for _, report := range reports {
+		byName[report.Name] = report
+	}
+
+	type sums struct{ assigned, activity, balance cc.Money }
+	zero := func() sums {
+		return sums{cc.NewMoney(0, ISOCode), cc.NewMoney(0, ISOCode), cc.NewMoney(0, ISOCode)}
+	}
+	add := func(into *sums, from sums) error {
+		var err error
+		if into.assigned, err = into.assigned.Add(from.assigned); err != nil {
+			return err
+		}
+		if into.activity, err = into.activity.Add(from.activity); err != nil {
+			return err
+		}
+		into.balance, err = into.balance.Add(from.balance)
+		return err
+	}
+	formatRow := func(label string, figures sums) []string {
+		balance := figures.balance.String()
+		if figures.balance.IsNegative() {
+			balance = paintOverspent(balance)
+		}
+		return []string{label, figures.assigned.String(), figures.activity.String(), balance}
+	}
+
+	rows := [][]string{}
+	total := zero()
+	for _, section := range sections {
+		rows = append(rows, []string{section.name})
+		subtotal := zero()
+		for _, category := range section.categories {
+			figures := zero()
+			if report, ok := byName[category]; ok {
+				figures = sums{
+					cc.NewMoney(report.Assigned, ISOCode),
+					cc.NewMoney(report.Activity, ISOCode),
+					cc.NewMoney(report.Balance, ISOCode),
+				}
+			}
+			rows = append(rows, formatRow("  "+category, figures))
+			if err := add(&subtotal, figures); err != nil {
+				return nil, err
+			}
+		}
+		rows = append(rows, formatRow("  Total "+section.name, subtotal))
+		if err := add(&total, subtotal); err != nil {
+			return nil, err
+		}
+	}
+	rows = append(rows, formatRow("TOTAL", total))
+	return rows, nil
+}
+
+// signed formats an amount with a sign, even if positive.
+func signed(m cc.Money) string {
+	if m.IsPositive() {
+		return "+" + m.String()
+	}
+	return m.String()
+}
+
+// printReportMatrix prints a figure of each category of the budget in
+// view for each month in the range given to a report.
+func (s *State) printReportMatrix(c *handlerContext, months []time.Time) error {
+	c.args.trackOptArgs(&c.cmd, "show")
+	field, _ := c.args.pfx()
+	if field == "" {
+		field = "activity"
+	}
+	if _, ok := reportFields[field]; !ok {
+		return newCLIError(errKindUsage, "cannot show '%s'; show assigned, activity or balance", field)
+	}
+	c.args.trackOptArgs(&c.cmd, "deltas")
+	deltas, _ := c.args.pfx()
+
+	reports, err := s.fetchCategoryReports(months)
+	if err != nil {
+		return err
+	}
+	categories := []string{}
+	for _, monthReports := range reports {
+		for _, report := range monthReports {
+			if !slices.Contains(categories, report.Name) {
+				categories = append(categories, report.Name)
+			}
+		}
+	}
+	if len(categories) == 0 {
+		fmt.Println("Nothing to report.")
+		return nil
+	}
+	sections, err := s.reportSections(categories)
+	if err != nil {
+		return err
+	}
+
+	headers, rows, err := reportMatrix(months, reports, sections, field, deltas == "SET", s.homeCurrency())
+	if err != nil {
+		return fmt.Errorf("could not total report: %w", err)
+	}
+	fmt.Printf("Category %s under budget %s, %s to %s:\n", field, s.Session.ActiveBudget.Name,
+		months[0].Format(dates.MonthLayout), months[len(months)-1].Format(dates.MonthLayout))
+	fmt.Print(makeTable(headers, rows))
+	return nil
+}
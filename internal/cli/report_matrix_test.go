@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestReportMatrix(t *testing.T) {
+	months := []time.Time{
+		time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+	}
+	reports := [][]*pgo.CategoryReport{
+		{
+			{Name: "Groceries", Activity: -30000, Balance: 5000},
+			{Name: "Dining", Activity: -10000, Balance: 0},
+			{Name: "Rent", Activity: -120000, Balance: 0},
+		},
+		{
+			{Name: "Groceries", Activity: -25000, Balance: 10000},
+			{Name: "Rent", Activity: -120000, Balance: 0},
+		},
+	}
+	sections := []reportSection{
+		{name: "Food", categories: []string{"Dining", "Groceries"}},
+		{name: "Ungrouped", categories: []string{"Rent"}},
+	}
+
+	tests := []struct {
+		name            string
+		field           string
+		deltas          bool
+		sections        []reportSection
+		expectedHeaders []string
+		expectedRows    [][]string
+	}{
+		{
+			name:            "activity by group",
+			field:           "activity",
+			sections:        sections,
+			expectedHeaders: []string{"CATEGORY", "2026-08", "2026-09", "TOTAL"},
+			expectedRows: [][]string{
+				{"Food"},
+				{"  Dining", "-$100.00", "$0.00", "-$100.00"},
+				{"  Groceries", "-$300.00", "-$250.00", "-$550.00"},
+				{"  Total Food", "-$400.00", "-$250.00", "-$650.00"},
+				{"Ungrouped"},
+				{"  Rent", "-$1,200.00", "-$1,200.00", "-$2,400.00"},
+				{"TOTAL", "-$1,600.00", "-$1,450.00", "-$3,050.00"},
+			},
+		},
+		{
+			name:            "balances are not totalled over time",
+			field:           "balance",
+			deltas:          true,
+			sections:        []reportSection{{name: "Ungrouped", categories: []string{"Dining", "Groceries"}}},
+			expectedHeaders: []string{"CATEGORY", "2026-08", "2026-09"},
+			expectedRows: [][]string{
+				{"Dining", "$0.00", "$0.00 ($0.00)"},
+				{"Groceries", "$50.00", "$100.00 (+$50.00)"},
+				{"TOTAL", "$50.00", "$100.00 (+$50.00)"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, rows, err := reportMatrix(months, reports, tt.sections, tt.field, tt.deltas, "USD")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(headers, tt.expectedHeaders) {
+				t.Errorf("expected headers %q, got %q", tt.expectedHeaders, headers)
+			}
+			if !slices.EqualFunc(rows, tt.expectedRows, slices.Equal) {
+				t.Errorf("expected rows %q, got %q", tt.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestCategoryTree(t *testing.T) {
+	reports := []*pgo.CategoryReport{
+		{Name: "Groceries", Assigned: 40000, Activity: -45000, Balance: -5000},
+		{Name: "Dining", Assigned: 10000, Activity: -2500, Balance: 7500},
+		{Name: "Gifts", Assigned: 5000, Activity: 0, Balance: 5000},
+	}
+	sections := []reportSection{
+		{name: "Food", categories: []string{"Dining", "Groceries"}},
+		{name: "Ungrouped", categories: []string{"Gifts"}},
+	}
+	paint := func(text string) string { return "!" + text }
+
+	rows, err := categoryTree(reports, sections, "USD", paint)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"Food"},
+		{"  Dining", "$100.00", "-$25.00", "$75.00"},
+		{"  Groceries", "$400.00", "-$450.00", "!-$50.00"},
+		{"  Total Food", "$500.00", "-$475.00", "$25.00"},
+		{"Ungrouped"},
+		{"  Gifts", "$50.00", "$0.00", "$50.00"},
+		{"  Total Ungrouped", "$50.00", "$0.00", "$50.00"},
+		{"TOTAL", "$550.00", "-$475.00", "$75.00"},
+	}
+	if !slices.EqualFunc(rows, expected, slices.Equal) {
+		t.Errorf("expected rows %q, got %q", expected, rows)
+	}
+}
+
+func TestSectionsByGroup(t *testing.T) {
+	groups := []*pgo.Group{}
+	for i, name := range []string{"Needs", "Fun"} {
+		group := &pgo.Group{MetaData: pgo.MetaData{Name: name}}
+		group.ID[15] = byte(i + 1)
+		groups = append(groups, group)
+	}
+	all := []*pgo.Category{
+		{MetaData: pgo.MetaData{Name: "Rent"}, GroupID: &groups[0].ID},
+		{MetaData: pgo.MetaData{Name: "Groceries"}, GroupID: &groups[0].ID},
+		{MetaData: pgo.MetaData{Name: "Dining"}, GroupID: &groups[1].ID},
+		{MetaData: pgo.MetaData{Name: "Gifts"}},
+	}
+
+	sections := sectionsByGroup([]string{"Rent", "Groceries", "Gifts", "Refunds"}, groups, all)
+	expected := []reportSection{
+		{name: "Needs", categories: []string{"Groceries", "Rent"}},
+		{name: "Ungrouped", categories: []string{"Gifts", "Refunds"}},
+	}
+	if len(sections) != len(expected) {
+		t.Fatalf("expected %d sections, but got %+v", len(expected), sections)
+	}
+	for i := range expected {
+		if sections[i].name != expected[i].name || !slices.Equal(sections[i].categories, expected[i].categories) {
+			t.Errorf("expected section %+v, but got %+v", expected[i], sections[i])
+		}
+	}
+}
@@ -1,52 +1,221 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
 
 	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
 )
 
-func findBudgetByName(name string, budgets []*pgo.Budget) (*pgo.Budget, error) {
-	for i := range len(budgets) {
-		if name == budgets[i].Name {
-			return budgets[i], nil
+// minIDPrefixLength is the fewest characters of an ID
+// that may be used to refer to a resource.
+const minIDPrefixLength = 4
+
+// resolveResource returns the single resource among items that the
+// query refers to. In order of precedence, a query may be a resource's
+// exact name, its full ID, its name in any case, a unique prefix of its
+// name, or a unique prefix of its ID. If the query matches more than one
+// resource at the same level of precedence, an ambiguity error listing
+// the candidates is returned instead.
+func resolveResource[T any](kind, query string, items []T, nameOf, idOf func(T) string) (T, error) {
+	return resolveAmong(kind, query, items, nameOf, idOf, true)
+}
+
+// resolveWhole is as resolveResource, except that a query must be the
+// whole of a resource's name or ID, making it suitable where a query
+// matching nothing names a resource yet to be created.
+func resolveWhole[T any](kind, query string, items []T, nameOf, idOf func(T) string) (T, error) {
+	return resolveAmong(kind, query, items, nameOf, idOf, false)
+}
+
+func resolveAmong[T any](kind, query string, items []T, nameOf, idOf func(T) string, byPrefix bool) (T, error) {
+	var zero T
+	lowerQuery := strings.ToLower(query)
+
+	resolve := func(matches []T) (T, bool, error) {
+		switch len(matches) {
+		case 0:
+			return zero, false, nil
+		case 1:
+			return matches[0], true, nil
+		default:
+			candidates := ExtractStrings(matches, func(item T) string {
+				return fmt.Sprintf("'%s' (%s)", nameOf(item), firstNChars(idOf(item), 8))
+			})
+			return zero, false, newCLIError(errKindUsage, "'%s' matches more than one of the %s: %s", query, kind, strings.Join(candidates, ", "))
 		}
 	}
-	return nil, fmt.Errorf("no budgets found with provided name '%s'", name)
-}
 
-func findAccountByName(name string, accounts []*pgo.Account) (*pgo.Account, error) {
-	for i := range len(accounts) {
-		if name == accounts[i].Name {
-			return accounts[i], nil
+	levels := []func(item T) bool{
+		func(item T) bool { return nameOf(item) == query },
+		func(item T) bool { return strings.EqualFold(idOf(item), query) },
+		func(item T) bool { return strings.EqualFold(nameOf(item), query) },
+	}
+	if byPrefix {
+		levels = append(levels, func(item T) bool {
+			return strings.HasPrefix(strings.ToLower(nameOf(item)), lowerQuery) ||
+				(len(query) >= minIDPrefixLength && strings.HasPrefix(strings.ToLower(idOf(item)), lowerQuery))
+		})
+	}
+	for _, matchesQuery := range levels {
+		matches := []T{}
+		for _, item := range items {
+			if matchesQuery(item) {
+				matches = append(matches, item)
+			}
+		}
+		if item, found, err := resolve(matches); found || err != nil {
+			return item, err
 		}
 	}
-	return nil, fmt.Errorf("no accounts found with provided name '%s'", name)
+
+	suggestion := didYouMean(query, ExtractStrings(items, nameOf))
+	return zero, newCLIError(errKindNotFound, "no %s found with provided name '%s'%s", kind, query, suggestion)
 }
 
-func findGroupByName(name string, groups []*pgo.Group) (*pgo.Group, error) {
-	for i := range len(groups) {
-		if name == groups[i].Name {
-			return groups[i], nil
+// createFound makes a resource through create, returning the ID of the
+// one resource listed afterwards which was not listed beforehand, so that
+// it may be told apart from any others of the same name. The ID is empty
+// if the new resource could not be told apart.
+func createFound[T any](list func() ([]T, error), idOf func(T) string, create func() error) (string, error) {
+	before, err := list()
+	if err != nil {
+		return "", err
+	}
+	if err := create(); err != nil {
+		return "", err
+	}
+	after, err := list()
+	if err != nil {
+		slog.Warn("could not find created resource", slog.String("error", err.Error()))
+		return "", nil
+	}
+	seen := map[string]bool{}
+	for _, item := range before {
+		seen[idOf(item)] = true
+	}
+	created := []string{}
+	for _, item := range after {
+		if !seen[idOf(item)] {
+			created = append(created, idOf(item))
 		}
 	}
-	return nil, fmt.Errorf("no groups found with provided name '%s'", name)
+	if len(created) != 1 {
+		slog.Warn("could not tell created resource apart", slog.Int("new", len(created)))
+		return "", nil
+	}
+	return created[0], nil
 }
 
-func findCategoryByName(name string, categories []*pgo.Category) (*pgo.Category, error) {
-	for i := range len(categories) {
-		if name == categories[i].Name {
-			return categories[i], nil
-		}
+func resolveBudget(query string, budgets []*pgo.Budget) (*pgo.Budget, error) {
+	return resolveResource("budgets", query, budgets,
+		func(b *pgo.Budget) string { return b.Name },
+		func(b *pgo.Budget) string { return b.ID.String() })
+}
+
+func resolveAccount(query string, accounts []*pgo.Account) (*pgo.Account, error) {
+	return resolveResource("accounts", query, accounts,
+		func(a *pgo.Account) string { return a.Name },
+		func(a *pgo.Account) string { return a.ID.String() })
+}
+
+func resolveGroup(query string, groups []*pgo.Group) (*pgo.Group, error) {
+	return resolveResource("groups", query, groups,
+		func(g *pgo.Group) string { return g.Name },
+		func(g *pgo.Group) string { return g.ID.String() })
+}
+
+func resolveCategory(query string, categories []*pgo.Category) (*pgo.Category, error) {
+	return resolveResource("categories", query, categories,
+		func(c *pgo.Category) string { return c.Name },
+		func(c *pgo.Category) string { return c.ID.String() })
+}
+
+func resolvePayee(query string, payees []*pgo.Payee) (*pgo.Payee, error) {
+	return resolveResource("payees", query, payees,
+		func(p *pgo.Payee) string { return p.Name },
+		func(p *pgo.Payee) string { return p.ID.String() })
+}
+
+// countTxns returns the number of transactions in the
+// given budget which match the given query parameters.
+func (s *State) countTxns(bID string, params url.Values) (int, error) {
+	query := ""
+	if len(params) > 0 {
+		query = "?" + params.Encode()
+	}
+	txns, err := s.GetTxns(bID, query)
+	if err != nil {
+		return 0, err
 	}
-	return nil, fmt.Errorf("no categories found with provided name '%s'", name)
+	return len(txns), nil
 }
 
-func findPayeeByName(name string, payees []*pgo.Payee) (*pgo.Payee, error) {
-	for i := range len(payees) {
-		if name == payees[i].Name {
-			return payees[i], nil
-		}
+// resolveAccountInView returns the account in the budget
+// in view which the given query refers to.
+func (s *State) resolveAccountInView(query string) (*pgo.Account, error) {
+	accounts, err := s.GetAccounts(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	return resolveAccount(query, accounts)
+}
+
+// resolveGroupName returns the name of the group in
+// the budget in view which the given query refers to.
+func (s *State) resolveGroupName(query string) (string, error) {
+	groups, err := s.GetGroups(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return "", err
+	}
+	group, err := resolveGroup(query, groups)
+	if err != nil {
+		return "", err
+	}
+	return group.Name, nil
+}
+
+// resolveCategoryName returns the name of the category in
+// the budget in view which the given query refers to.
+func (s *State) resolveCategoryName(query string) (string, error) {
+	categories, err := s.GetCategories(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return "", err
+	}
+	category, err := resolveCategory(query, categories)
+	if err != nil {
+		return "", err
+	}
+	return category.Name, nil
+}
+
+// resolvePayeeName returns the name of the payee in the budget in
+// view which the given query refers to.
+func (s *State) resolvePayeeName(query string) (string, error) {
+	payees, err := s.GetPayees(s.Session.ActiveBudget.ID.String(), "")
+	if err != nil {
+		return "", err
+	}
+	return payeeNameOrNew(query, payees)
+}
+
+// payeeNameOrNew returns the name of the payee which the given query
+// refers to. As a payee may be new to the budget, the query must be a
+// payee's whole name or ID, and is itself returned if it matches none,
+// so that a new payee is never mistaken for one whose name it begins.
+func payeeNameOrNew(query string, payees []*pgo.Payee) (string, error) {
+	payee, err := resolveWhole("payees", query, payees,
+		func(p *pgo.Payee) string { return p.Name },
+		func(p *pgo.Payee) string { return p.ID.String() })
+	var cliErr *cliError
+	if errors.As(err, &cliErr) && cliErr.kind == errKindNotFound {
+		return query, nil
+	}
+	if err != nil {
+		return "", err
 	}
-	return nil, fmt.Errorf("no payees found with provided name '%s'", name)
+	return payee.Name, nil
 }
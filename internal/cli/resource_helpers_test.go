@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestResolveResource(t *testing.T) {
+	type resource struct {
+		name string
+		id   string
+	}
+	resources := []resource{
+		{name: "Checking", id: "3f2b8c1e-0000-4000-8000-000000000001"},
+		{name: "Chase Savings", id: "3f2b9d2f-0000-4000-8000-000000000002"},
+		{name: "checking", id: "a1b2c3d4-0000-4000-8000-000000000003"},
+		{name: "Brokerage", id: "b7e6f5a4-0000-4000-8000-000000000004"},
+	}
+	nameOf := func(r resource) string { return r.name }
+	idOf := func(r resource) string { return r.id }
+
+	tests := []struct {
+		query        string
+		expectedName string
+		wantKind     errorKind
+		wantErr      bool
+	}{
+		{query: "Checking", expectedName: "Checking"},
+		{query: "checking", expectedName: "checking"},
+		{query: "brokerage", expectedName: "Brokerage"},
+		{query: "Bro", expectedName: "Brokerage"},
+		{query: "Chase", expectedName: "Chase Savings"},
+		{query: "a1b2", expectedName: "checking"},
+		{query: "B7E6F5A4-0000-4000-8000-000000000004", expectedName: "Brokerage"},
+		{query: "CHECKING", wantErr: true, wantKind: errKindUsage},
+		{query: "Ch", wantErr: true, wantKind: errKindUsage},
+		{query: "3f2b", wantErr: true, wantKind: errKindUsage},
+		{query: "Chekcing", wantErr: true, wantKind: errKindNotFound},
+		{query: "a1b", wantErr: true, wantKind: errKindNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			actual, err := resolveResource("accounts", tt.query, resources, nameOf, idOf)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if kind := classifyError(err).kind; kind != tt.wantKind {
+					t.Fatalf("expected error kind %s, but got %s: %v", tt.wantKind, kind, err)
+				}
+				return
+			}
+			if actual.name != tt.expectedName {
+				t.Fatalf("expected resource %s, but got %s", tt.expectedName, actual.name)
+			}
+		})
+	}
+}
+
+func TestPayeeNameOrNew(t *testing.T) {
+	payees := []*pgo.Payee{}
+	for i, name := range []string{"Starbucks", "Star Market"} {
+		payee := &pgo.Payee{MetaData: pgo.MetaData{Name: name}}
+		payee.ID[15] = byte(i + 1)
+		payees = append(payees, payee)
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "Starbucks", expected: "Starbucks"},
+		{query: "starbucks", expected: "Starbucks"},
+		{query: payees[1].ID.String(), expected: "Star Market"},
+		{query: "Starb", expected: "Starb"},
+		{query: "Star", expected: "Star"},
+		{query: "Blue Bottle", expected: "Blue Bottle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			actual, err := payeeNameOrNew(tt.query, payees)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected payee %s, but got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateFound(t *testing.T) {
+	type resource struct{ name, id string }
+	idOf := func(r resource) string { return r.id }
+	listed := []resource{{name: "Checking", id: "1"}}
+	list := func() ([]resource, error) { return slices.Clone(listed), nil }
+
+	id, err := createFound(list, idOf, func() error {
+		listed = append(listed, resource{name: "Checking", id: "2"})
+		return nil
+	})
+	if err != nil || id != "2" {
+		t.Errorf("expected the new resource of the same name to be found, but got %q (error: %v)", id, err)
+	}
+
+	id, err = createFound(list, idOf, func() error {
+		listed = append(listed, resource{name: "Savings", id: "3"}, resource{name: "Savings", id: "4"})
+		return nil
+	})
+	if err != nil || id != "" {
+		t.Errorf("expected no resource when several appeared, but got %q (error: %v)", id, err)
+	}
+}
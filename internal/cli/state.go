@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/YouWantToPinch/pincher-cli/internal/config"
 	file "github.com/YouWantToPinch/pincher-cli/internal/filemgr"
@@ -20,6 +23,7 @@ type State struct {
 	Client   *pgo.Client
 	Session  *cliSession
 	styles   *styles
+	scanner  *bufio.Scanner
 }
 
 // GetBudget goes through the Client to retrieve a
@@ -250,6 +254,22 @@ func (s *State) SaveCacheFile() error {
 	return nil
 }
 
+// readLine prints the given prompt and returns the next
+// line of user input, sharing the scanner used by the REPL.
+func (s *State) readLine(prompt string) (string, error) {
+	if s.scanner == nil {
+		s.scanner = bufio.NewScanner(os.Stdin)
+	}
+	fmt.Print(prompt)
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("no input to read")
+	}
+	return strings.TrimSpace(s.scanner.Text()), nil
+}
+
 func (s *State) NewSession() {
 	s.Session = &cliSession{}
 	s.Session.Init()
@@ -281,6 +301,9 @@ func (s *State) getUnstyledPrompt() string {
 	} else if s.Session.ActiveBudget.Name == "" {
 		return fmt.Sprintf("p¢/%s > ", s.Session.ActiveUser.Username)
 	} else {
+		if ready, ok := s.promptReadyToAssign(); ok {
+			return fmt.Sprintf("p¢/%s[%s %s] > ", s.Session.ActiveUser.Username, s.Session.ActiveBudget.Name, ready)
+		}
 		return fmt.Sprintf("p¢/%s[%s] > ", s.Session.ActiveUser.Username, s.Session.ActiveBudget.Name)
 	}
 }
@@ -303,7 +326,15 @@ func (s *State) getStyledPrompt() string {
 		} else {
 			lbr := s.styles.Green.Render("[")
 			rbr := s.styles.Green.Render("]")
-			return p + cent + slash + s.styles.White.Render(s.Session.ActiveUser.Username) + lbr + s.styles.White.Render(s.Session.ActiveBudget.Name) + rbr + chev
+			budget := s.styles.White.Render(s.Session.ActiveBudget.Name)
+			if ready, ok := s.promptReadyToAssign(); ok {
+				readyStyle := s.styles.Green
+				if ready.IsNegative() {
+					readyStyle = s.styles.Red
+				}
+				budget += " " + readyStyle.Render(ready.String())
+			}
+			return p + cent + slash + s.styles.White.Render(s.Session.ActiveUser.Username) + lbr + budget + rbr + chev
 		}
 	}
 }
@@ -314,6 +345,10 @@ type cliSession struct {
 	ActiveUser      pgo.User
 	ActiveBudget    pgo.Budget
 	CommandRegistry *commandRegistry
+	UndoStack       undoStack
+	// promptReady keeps the money left to assign shown in the prompt,
+	// which would otherwise be worked out anew each time it is drawn
+	promptReady *promptReadiness
 }
 
 // Init preregisters all commands to the internal command registry.
@@ -326,6 +361,7 @@ func (s *cliSession) Init() {
 	// preregister ALL commands
 	s.CommandRegistry.batchRegistration(makeBaseCommandHandlers(), Preregistered)
 	s.CommandRegistry.preregister(makeBudgetCommandHandler())
+	s.CommandRegistry.preregister(makeUndoCommandHandler())
 	s.CommandRegistry.batchRegistration(makeResourceCommandHandlers(), Preregistered)
 	// register base commands
 	s.CommandRegistry.batchRegistration(makeBaseCommandHandlers(), Registered)
@@ -334,6 +370,7 @@ func (s *cliSession) Init() {
 func (s *cliSession) OnLogin(user pgo.User) {
 	// register commands that require login
 	s.CommandRegistry.register("budget")
+	s.CommandRegistry.register("undo")
 	s.ActiveUser = user
 	fmt.Printf("Logged in as user: %s\n", s.ActiveUser.Username)
 }
@@ -341,10 +378,13 @@ func (s *cliSession) OnLogin(user pgo.User) {
 func (s *cliSession) OnViewBudget() {
 	// register commands that require viewing a budget
 	s.CommandRegistry.batchRegistration(makeResourceCommandHandlers(), Registered)
+	s.promptReady = nil
 }
 
 func (s *cliSession) OnLogout() {
 	// deregister commands
 	s.ActiveUser = pgo.User{}
+	s.UndoStack.clear()
+	s.promptReady = nil
 	s.CommandRegistry.deregisterNonBaseCommands()
 }
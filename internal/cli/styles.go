@@ -7,10 +7,27 @@ type styles struct {
 	Orange lipgloss.Style
 	Green  lipgloss.Style
 	White  lipgloss.Style
+	Red    lipgloss.Style
 }
 
+// paint returns a function rendering text in one of the styles of
+// the CLI, chosen as in s.paint(red), or leaving it plain if the CLI
+// is unstyled.
+func (s *State) paint(style func(*styles) lipgloss.Style) func(string) string {
+	if s.styles == nil {
+		return func(text string) string { return text }
+	}
+	chosen := style(s.styles)
+	return func(text string) string { return chosen.Render(text) }
+}
+
+// red and green choose among the styles of the CLI for paint.
+func red(s *styles) lipgloss.Style   { return s.Red }
+func green(s *styles) lipgloss.Style { return s.Green }
+
 func (s *styles) Init() {
 	s.Orange = lipgloss.NewStyle().Foreground(lipgloss.Color("#F79269"))
 	s.White = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
 	s.Green = lipgloss.NewStyle().Foreground(lipgloss.Color("#4FD6BE"))
+	s.Red = lipgloss.NewStyle().Foreground(lipgloss.Color("#F7768E"))
 }
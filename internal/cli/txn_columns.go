@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// txnRow is a transaction as listed, along with
+// what is needed to describe it beyond its details.
+type txnRow struct {
+	txn *pgo.TransactionDetail
+	// iso is the ISO Code of the currency of its account
+	iso string
+	// balance is the balance of its account after it,
+	// if running balances were asked for
+	balance int64
+}
+
+// txnColumn is a column which a list of transactions may show.
+type txnColumn struct {
+	name   string
+	header string
+	cell   func(row txnRow) string
+}
+
+// txnColumns are the columns which a list of transactions may
+// show, in the order in which they are shown by default.
+var txnColumns = []txnColumn{
+	{name: "date", header: "DATE", cell: func(row txnRow) string {
+		return row.txn.TransactionDate.Format(dates.DayLayout)
+	}},
+	{name: "account", header: "ACCOUNT", cell: func(row txnRow) string {
+		return row.txn.AccountName
+	}},
+	{name: "payee", header: "PAYEE", cell: func(row txnRow) string {
+		return row.txn.PayeeName
+	}},
+	{name: "category", header: "CATEGORY", cell: func(row txnRow) string {
+		return txnCategory(row.txn)
+	}},
+	{name: "amount", header: "AMOUNT", cell: func(row txnRow) string {
+		return cc.Format(row.txn.TotalAmount, row.iso, true)
+	}},
+	{name: "cleared", header: "CLR", cell: func(row txnRow) string {
+		if row.txn.Cleared {
+			return "✓"
+		}
+		return ""
+	}},
+	{name: "balance", header: "BALANCE", cell: func(row txnRow) string {
+		return cc.Format(row.balance, row.iso, true)
+	}},
+	{name: "notes", header: "NOTES", cell: func(row txnRow) string {
+		return firstNChars(row.txn.Notes, 25)
+	}},
+	{name: "id", header: "ID", cell: func(row txnRow) string {
+		return row.txn.ID.String()
+	}},
+}
+
+// defaultTxnColumns are the names of the columns shown unless others are chosen.
+var defaultTxnColumns = []string{"date", "account", "payee", "category", "amount", "cleared", "notes"}
+
+// chooseTxnColumns returns the columns with the given comma-separated
+// names, in the order given, or the default columns if none are given.
+func chooseTxnColumns(names string) ([]txnColumn, error) {
+	chosen := defaultTxnColumns
+	if names != "" {
+		chosen = strings.Split(names, ",")
+	}
+	known := ExtractStrings(txnColumns, func(col txnColumn) string { return col.name })
+	columns := []txnColumn{}
+	for _, name := range chosen {
+		name = strings.ToLower(strings.TrimSpace(name))
+		i := slices.Index(known, name)
+		if i < 0 {
+			return nil, newCLIError(errKindUsage, "unknown column '%s'%s; choose from: %s", name, didYouMean(name, known), strings.Join(known, ", "))
+		}
+		columns = append(columns, txnColumns[i])
+	}
+	return columns, nil
+}
+
+// txnCategory describes where the money of a transaction went: the
+// account on the other side of a transfer, its only category, or
+// how many categories it was split between.
+func txnCategory(txn *pgo.TransactionDetail) string {
+	switch {
+	case txn.TransferAccountName != "" && txn.TotalAmount < 0:
+		return "Transfer to " + txn.TransferAccountName
+	case txn.TransferAccountName != "":
+		return "Transfer from " + txn.TransferAccountName
+	case len(txn.Splits) == 1:
+		for category := range txn.Splits {
+			return category
+		}
+	case len(txn.Splits) > 1:
+		return fmt.Sprintf("Split (%d)", len(txn.Splits))
+	}
+	return ""
+}
+
+// splitRows returns a row for each split of a transaction split between
+// categories, to be printed beneath it. Only the category and amount
+// columns are filled in.
+func splitRows(row txnRow, columns []txnColumn) [][]string {
+	if len(row.txn.Splits) < 2 {
+		return nil
+	}
+	categories := make([]string, 0, len(row.txn.Splits))
+	for category := range row.txn.Splits {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+
+	rows := [][]string{}
+	for i, category := range categories {
+		branch := "├ "
+		if i == len(categories)-1 {
+			branch = "└ "
+		}
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			switch col.name {
+			case "category":
+				cells[j] = branch + category
+			case "amount":
+				cells[j] = cc.Format(row.txn.Splits[category], row.iso, true)
+			}
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// runningBalances returns the balance of each account after each of
+// the given transactions, keyed by transaction ID. The transactions
+// should be every one of the budget, such that balances start at zero.
+func runningBalances(txns []*pgo.TransactionDetail) map[string]int64 {
+	ordered := slices.Clone(txns)
+	sortTxns(ordered, "date", false)
+	balances := map[string]int64{}
+	after := map[string]int64{}
+	for _, txn := range ordered {
+		balances[txn.AccountName] += txn.TotalAmount
+		after[txn.ID.String()] = balances[txn.AccountName]
+	}
+	return after
+}
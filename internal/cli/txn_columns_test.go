@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func TestChooseTxnColumns(t *testing.T) {
+	tests := []struct {
+		names    string
+		expected []string
+		wantErr  bool
+	}{
+		{names: "", expected: defaultTxnColumns},
+		{names: "amount,date", expected: []string{"amount", "date"}},
+		{names: " Date , BALANCE", expected: []string{"date", "balance"}},
+		{names: "date,amonut", wantErr: true},
+		{names: "date,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.names, func(t *testing.T) {
+			columns, err := chooseTxnColumns(tt.names)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			actual := ExtractStrings(columns, func(col txnColumn) string { return col.name })
+			if !tt.wantErr && !slices.Equal(actual, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTxnCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		txn      *pgo.TransactionDetail
+		expected string
+	}{
+		{
+			name:     "single category",
+			txn:      &pgo.TransactionDetail{TotalAmount: -500, Splits: map[string]int64{"Dining": -500}},
+			expected: "Dining",
+		},
+		{
+			name:     "split",
+			txn:      &pgo.TransactionDetail{TotalAmount: -900, Splits: map[string]int64{"Dining": -500, "Groceries": -300, "Fuel": -100}},
+			expected: "Split (3)",
+		},
+		{
+			name:     "transfer out",
+			txn:      &pgo.TransactionDetail{TotalAmount: -500, TransferAccountName: "Savings"},
+			expected: "Transfer to Savings",
+		},
+		{
+			name:     "transfer in",
+			txn:      &pgo.TransactionDetail{TotalAmount: 500, TransferAccountName: "Checking"},
+			expected: "Transfer from Checking",
+		},
+		{
+			name:     "none",
+			txn:      &pgo.TransactionDetail{TotalAmount: 500},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := txnCategory(tt.txn); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSplitRows(t *testing.T) {
+	columns, _ := chooseTxnColumns("date,category,amount")
+	row := txnRow{
+		txn: &pgo.TransactionDetail{TotalAmount: -6000, Splits: map[string]int64{"Groceries": -3500, "Dining": -2500}},
+		iso: "USD",
+	}
+	expected := [][]string{
+		{"", "├ Dining", "-$25.00"},
+		{"", "└ Groceries", "-$35.00"},
+	}
+	actual := splitRows(row, columns)
+	if !slices.EqualFunc(actual, expected, slices.Equal) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	row.txn.Splits = map[string]int64{"Dining": -6000}
+	if actual := splitRows(row, columns); len(actual) != 0 {
+		t.Errorf("expected no rows for a single category, got %q", actual)
+	}
+}
+
+func TestRunningBalances(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+	txns := []*pgo.TransactionDetail{
+		{AccountName: "Checking", TransactionDate: day(9), TotalAmount: -2500},
+		{AccountName: "Checking", TransactionDate: day(1), TotalAmount: 100000},
+		{AccountName: "Savings", TransactionDate: day(3), TotalAmount: 50000},
+		{AccountName: "Checking", TransactionDate: day(3), TotalAmount: -50000},
+	}
+	for i, txn := range txns {
+		txn.ID[15] = byte(i + 1)
+	}
+	expected := []int64{47500, 100000, 50000, 50000}
+
+	balances := runningBalances(txns)
+	for i, txn := range txns {
+		if balances[txn.ID.String()] != expected[i] {
+			t.Errorf("expected balance %d after transaction %d, got %d", expected[i], i, balances[txn.ID.String()])
+		}
+	}
+}
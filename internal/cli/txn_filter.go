@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+// txnFilter narrows down a list of transactions by what
+// the server cannot be asked to filter them by. Its zero
+// value lets every transaction through.
+type txnFilter struct {
+	// startDate and endDate bound transaction dates inclusively,
+	// written as YYYY-MM-DD; either may be empty
+	startDate, endDate string
+	// minAmount and maxAmount bound the size of
+	// transactions, whether they be inflows or outflows
+	minAmount, maxAmount *int64
+	cleared              *bool
+	// notes is text which the notes of transactions
+	// must contain, in any case
+	notes         string
+	transfersOnly bool
+	// categories are those which transactions must have a
+	// split in, unless nil
+	categories map[string]bool
+}
+
+// matches reports whether a transaction passes the filter.
+func (f txnFilter) matches(txn *pgo.TransactionDetail) bool {
+	day := txn.TransactionDate.Format(dates.DayLayout)
+	if (f.startDate != "" && day < f.startDate) || (f.endDate != "" && day > f.endDate) {
+		return false
+	}
+	size := txn.TotalAmount
+	if size < 0 {
+		size = -size
+	}
+	if (f.minAmount != nil && size < *f.minAmount) || (f.maxAmount != nil && size > *f.maxAmount) {
+		return false
+	}
+	if f.cleared != nil && txn.Cleared != *f.cleared {
+		return false
+	}
+	if f.notes != "" && !strings.Contains(strings.ToLower(txn.Notes), strings.ToLower(f.notes)) {
+		return false
+	}
+	if f.transfersOnly && txn.TransferAccountName == "" {
+		return false
+	}
+	if f.categories != nil {
+		inCategory := false
+		for category := range txn.Splits {
+			inCategory = inCategory || f.categories[category]
+		}
+		if !inCategory {
+			return false
+		}
+	}
+	return true
+}
+
+// filterTxns returns the transactions which pass the filter.
+func filterTxns(txns []*pgo.TransactionDetail, f txnFilter) []*pgo.TransactionDetail {
+	filtered := []*pgo.TransactionDetail{}
+	for _, txn := range txns {
+		if f.matches(txn) {
+			filtered = append(filtered, txn)
+		}
+	}
+	return filtered
+}
+
+// txnOrderings compare transactions by the
+// fields which a list of them may be sorted by.
+var txnOrderings = map[string]func(a, b *pgo.TransactionDetail) int{
+	"date": func(a, b *pgo.TransactionDetail) int {
+		return a.TransactionDate.Compare(b.TransactionDate)
+	},
+	"amount": func(a, b *pgo.TransactionDetail) int {
+		return cmp.Compare(a.TotalAmount, b.TotalAmount)
+	},
+	"payee": func(a, b *pgo.TransactionDetail) int {
+		return cmp.Compare(strings.ToLower(a.PayeeName), strings.ToLower(b.PayeeName))
+	},
+}
+
+// sortTxns sorts transactions in place by the given field, keeping
+// those equal in it in date order. It panics on an unknown field;
+// check it against txnOrderings first.
+func sortTxns(txns []*pgo.TransactionDetail, by string, reverse bool) {
+	byField, byDate := txnOrderings[by], txnOrderings["date"]
+	slices.SortStableFunc(txns, func(a, b *pgo.TransactionDetail) int {
+		return cmp.Or(byField(a, b), byDate(a, b))
+	})
+	if reverse {
+		slices.Reverse(txns)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	pgo "github.com/YouWantToPinch/pincher-sdk-go/pinchergo"
+)
+
+func testTxns() []*pgo.TransactionDetail {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+	return []*pgo.TransactionDetail{
+		{Notes: "rent", TransactionDate: day(1), TotalAmount: -120000, PayeeName: "Landlord", Cleared: true, Splits: map[string]int64{"Rent": -120000}},
+		{Notes: "paycheck", TransactionDate: day(3), TotalAmount: 250000, PayeeName: "Employer", Cleared: true, Splits: map[string]int64{"Income": 250000}},
+		{Notes: "Weekly groceries", TransactionDate: day(5), TotalAmount: -8450, PayeeName: "grocer", Splits: map[string]int64{"Groceries": -8450}},
+		{Notes: "to savings", TransactionDate: day(6), TotalAmount: -50000, TransferAccountName: "Savings"},
+		{Notes: "dinner and groceries", TransactionDate: day(9), TotalAmount: -6000, PayeeName: "Market", Splits: map[string]int64{"Dining": -2500, "Groceries": -3500}},
+	}
+}
+
+func notesOf(txns []*pgo.TransactionDetail) []string {
+	return ExtractStrings(txns, func(t *pgo.TransactionDetail) string { return t.Notes })
+}
+
+func TestFilterTxns(t *testing.T) {
+	amount := func(a int64) *int64 { return &a }
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		filter   txnFilter
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			filter:   txnFilter{},
+			expected: []string{"rent", "paycheck", "Weekly groceries", "to savings", "dinner and groceries"},
+		},
+		{
+			name:     "dates are inclusive",
+			filter:   txnFilter{startDate: "2026-10-03", endDate: "2026-10-05"},
+			expected: []string{"paycheck", "Weekly groceries"},
+		},
+		{
+			name:     "open-ended dates",
+			filter:   txnFilter{startDate: "2026-10-06"},
+			expected: []string{"to savings", "dinner and groceries"},
+		},
+		{
+			name:     "amount range by size",
+			filter:   txnFilter{minAmount: amount(6000), maxAmount: amount(50000)},
+			expected: []string{"Weekly groceries", "to savings", "dinner and groceries"},
+		},
+		{
+			name:     "cleared",
+			filter:   txnFilter{cleared: &yes},
+			expected: []string{"rent", "paycheck"},
+		},
+		{
+			name:     "uncleared",
+			filter:   txnFilter{cleared: &no},
+			expected: []string{"Weekly groceries", "to savings", "dinner and groceries"},
+		},
+		{
+			name:     "notes in any case",
+			filter:   txnFilter{notes: "GROCERIES"},
+			expected: []string{"Weekly groceries", "dinner and groceries"},
+		},
+		{
+			name:     "transfers only",
+			filter:   txnFilter{transfersOnly: true},
+			expected: []string{"to savings"},
+		},
+		{
+			name:     "any split in group",
+			filter:   txnFilter{categories: map[string]bool{"Dining": true, "Rent": true}},
+			expected: []string{"rent", "dinner and groceries"},
+		},
+		{
+			name:     "empty group",
+			filter:   txnFilter{categories: map[string]bool{}},
+			expected: []string{},
+		},
+		{
+			name:     "combined",
+			filter:   txnFilter{notes: "groceries", cleared: &no, endDate: "2026-10-05"},
+			expected: []string{"Weekly groceries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := notesOf(filterTxns(testTxns(), tt.filter))
+			if !slices.Equal(actual, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSortTxns(t *testing.T) {
+	tests := []struct {
+		by       string
+		reverse  bool
+		expected []string
+	}{
+		{
+			by:       "date",
+			expected: []string{"rent", "paycheck", "Weekly groceries", "to savings", "dinner and groceries"},
+		},
+		{
+			by:       "date",
+			reverse:  true,
+			expected: []string{"dinner and groceries", "to savings", "Weekly groceries", "paycheck", "rent"},
+		},
+		{
+			by:       "amount",
+			expected: []string{"rent", "to savings", "Weekly groceries", "dinner and groceries", "paycheck"},
+		},
+		{
+			// payees compare in any case, and those without one come first
+			by:       "payee",
+			expected: []string{"to savings", "paycheck", "Weekly groceries", "rent", "dinner and groceries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			txns := testTxns()
+			// reverse the order given, so that it is not simply kept
+			slices.Reverse(txns)
+			sortTxns(txns, tt.by, tt.reverse)
+			actual := notesOf(txns)
+			if !slices.Equal(actual, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxUndoEntries is the most mutations that a session will remember.
+const maxUndoEntries = 50
+
+// mutation records a successful change made during a session,
+// along with the means to reverse it.
+type mutation struct {
+	description string
+	madeAt      time.Time
+	undo        func(s *State) error
+}
+
+// undoStack tracks the most recent mutations of a session.
+type undoStack struct {
+	entries []mutation
+}
+
+// push records a mutation, forgetting the oldest one
+// if the stack has grown beyond its limit.
+func (u *undoStack) push(description string, undo func(s *State) error) {
+	u.entries = append(u.entries, mutation{
+		description: description,
+		madeAt:      time.Now(),
+		undo:        undo,
+	})
+	if len(u.entries) > maxUndoEntries {
+		u.entries = u.entries[len(u.entries)-maxUndoEntries:]
+	}
+}
+
+// pop removes and returns the most recent mutation.
+func (u *undoStack) pop() (mutation, bool) {
+	if len(u.entries) == 0 {
+		return mutation{}, false
+	}
+	last := u.entries[len(u.entries)-1]
+	u.entries = u.entries[:len(u.entries)-1]
+	return last, true
+}
+
+func (u *undoStack) clear() {
+	u.entries = nil
+}
+
+// handlerUndo reverses the most recent mutation of the session,
+// or lists those which may be reversed.
+func handlerUndo(s *State, c *handlerContext) error {
+	action, _ := c.args.pfx()
+	if action != "" {
+		actions := s.Session.CommandRegistry.handlers[c.cmd.name].actions
+		if el, found := findCMDElementWithName(actions, action); found && el.name == "list" {
+			return handleUndoList(s, c)
+		}
+		return newCLIError(errKindUsage, "invalid action for command '%s': %s%s", c.cmd.name, action, didYouMean(action, elementNames(actions)))
+	}
+
+	last, ok := s.Session.UndoStack.pop()
+	if !ok {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+	if c.globals.dryRun {
+		s.Session.UndoStack.entries = append(s.Session.UndoStack.entries, last)
+		fmt.Printf("DRY RUN: would undo: %s\n", last.description)
+		return nil
+	}
+	err := last.undo(s)
+	if err != nil {
+		// keep the mutation around so that undoing it may be retried
+		s.Session.UndoStack.entries = append(s.Session.UndoStack.entries, last)
+		return fmt.Errorf("could not undo '%s': %w", last.description, err)
+	}
+	fmt.Printf("Undid: %s\n", last.description)
+	return nil
+}
+
+func handleUndoList(s *State, c *handlerContext) error {
+	entries := s.Session.UndoStack.entries
+	if len(entries) == 0 {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+	fmt.Println("Changes which may be undone, most recent first:")
+	column1 := []string{}
+	column2 := []string{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		column1 = append(column1, fmt.Sprintf("  %d. %s", len(entries)-i, entries[i].madeAt.Format("15:04:05")))
+		column2 = append(column2, entries[i].description)
+	}
+	fmt.Println(makeAlignedTable(column1, column2))
+	return nil
+}
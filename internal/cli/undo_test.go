@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUndoStack(t *testing.T) {
+	u := undoStack{}
+	for i := range maxUndoEntries + 5 {
+		u.push(fmt.Sprintf("change %d", i), func(s *State) error { return nil })
+	}
+	if len(u.entries) != maxUndoEntries {
+		t.Fatalf("expected %d entries, but got %d", maxUndoEntries, len(u.entries))
+	}
+	if u.entries[0].description != "change 5" {
+		t.Errorf("expected oldest entries to be forgotten, but oldest is: %s", u.entries[0].description)
+	}
+
+	last, ok := u.pop()
+	if !ok || last.description != fmt.Sprintf("change %d", maxUndoEntries+4) {
+		t.Errorf("expected most recent entry to be popped, but got: %s", last.description)
+	}
+
+	u.clear()
+	if _, ok := u.pop(); ok {
+		t.Errorf("expected empty stack after clear")
+	}
+}
+
+func TestHandlerUndoRejectsUnknownAction(t *testing.T) {
+	s := &State{Session: &cliSession{}}
+	s.Session.Init()
+	undone := false
+	s.Session.UndoStack.push("change", func(s *State) error {
+		undone = true
+		return nil
+	})
+
+	for _, action := range []string{"lsit", "foo"} {
+		c := &handlerContext{cmd: command{name: "undo", args: []string{action}}}
+		c.args.init(&c.cmd)
+		err := handlerUndo(s, c)
+		if classifyError(err) == nil || classifyError(err).kind != errKindUsage {
+			t.Errorf("undo %s: expected a usage error, but got: %v", action, err)
+		}
+	}
+	if undone || len(s.Session.UndoStack.entries) != 1 {
+		t.Errorf("expected a mistyped action to leave the change alone")
+	}
+}
@@ -2,19 +2,37 @@
 package config
 
 import (
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
 	file "github.com/YouWantToPinch/pincher-cli/internal/filemgr"
 )
 
 type ConfigSettings struct {
-	BaseURL         string `json:"db_url" smname:"Database URL" smdes:"URL of the server to connect to"`
-	CurrencyISOCode string `json:"currency_iso_code" smname:"Currency ISO" smdes:"The ISO Code of the currency desired for monetary visualization"`
-	StayLoggedIn    bool   `json:"stay_logged_in" smname:"Stay Logged In" smdes:"Keep a login session alive on exit."`
-	VimKeysEnabled  bool   `json:"vim_keys_enabled" smname:"Vim Keys Enabled" smdes:"Use vim keys to navigate CLI menus."`
+	BaseURL             string `json:"db_url" smname:"Database URL" smdes:"URL of the server to connect to"`
+	CurrencyISOCode     string `json:"currency_iso_code" smname:"Currency ISO" smdes:"The ISO Code of the currency used by budgets not given one of their own"`
+	StayLoggedIn        bool   `json:"stay_logged_in" smname:"Stay Logged In" smdes:"Keep a login session alive on exit."`
+	VimKeysEnabled      bool   `json:"vim_keys_enabled" smname:"Vim Keys Enabled" smdes:"Use vim keys to navigate CLI menus."`
+	DateFormat          string `json:"date_format,omitempty" smname:"Date Format" smdes:"A local format for dates, accepted alongside YYYY-MM-DD, as in DD/MM/YYYY"`
+	PromptReadyToAssign bool   `json:"prompt_ready_to_assign,omitempty" smname:"Prompt Ready to Assign" smdes:"Show the money left to assign in the budget in view beside its name in the prompt"`
 }
 
+// AssignTemplate is a saved set of amounts to assign
+// to the categories of a budget, by category ID.
+type AssignTemplate map[string]cc.Money
+
 // Config represents a configuration specific to the local machine.
 type Config struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string            `json:"refresh_token"`
+	Aliases      map[string]string `json:"aliases,omitempty"` // user-defined command shortcuts
+	// BudgetCurrencies holds the ISO Codes of the currencies
+	// budgets are kept in, by budget ID.
+	BudgetCurrencies map[string]string `json:"budget_currencies,omitempty"`
+	// AccountCurrencies holds the ISO Codes of the currencies accounts
+	// were set to be kept in, by account ID. Accounts not held here are
+	// kept in the currency of their budget.
+	AccountCurrencies map[string]string `json:"account_currencies,omitempty"`
+	// AssignTemplates holds the assignment templates saved for each
+	// budget, by budget ID and then by template name.
+	AssignTemplates map[string]map[string]AssignTemplate `json:"assign_templates,omitempty"`
 	ConfigSettings
 }
 
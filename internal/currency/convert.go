@@ -0,0 +1,34 @@
+package currency
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Convert converts an amount in the smallest unit of one currency to
+// the smallest unit of another, given the value of one unit of the
+// first in units of the second. As with Evaluate, the result is
+// rounded to the nearest minor unit, with halves rounded away from zero.
+func Convert(amount int64, fromISO, toISO string, rate *big.Rat) (int64, error) {
+	if rate == nil || rate.Sign() <= 0 {
+		return 0, fmt.Errorf("exchange rate must be positive")
+	}
+	from, to := lookupOrDefault(fromISO), lookupOrDefault(toISO)
+	value := new(big.Rat).SetFrac64(amount, max(from.DecimalFactor, 1))
+	value.Mul(value, rate)
+	converted, err := roundToMinorUnit(value, max(to.DecimalFactor, 1))
+	if err != nil {
+		return 0, fmt.Errorf("could not convert %s to %s: %w", fromISO, toISO, err)
+	}
+	return converted, nil
+}
+
+// Convert converts m into the currency with the given ISO Code,
+// given the value of one unit of its currency in units of the other.
+func (m Money) Convert(ISOCode string, rate *big.Rat) (Money, error) {
+	converted, err := Convert(m.Amount, m.ISOCode, ISOCode, rate)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(converted, ISOCode), nil
+}
@@ -0,0 +1,40 @@
+package currency
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func Test_Convert(t *testing.T) {
+	tests := []struct {
+		input    int64
+		from, to string
+		rate     string
+		expected int64
+		wantErr  bool
+	}{
+		{input: 10000, from: "EUR", to: "USD", rate: "1.0845", expected: 10845},
+		{input: -10000, from: "EUR", to: "USD", rate: "1.0845", expected: -10845},
+		{input: 1, from: "EUR", to: "USD", rate: "1.5", expected: 2},
+		{input: -1, from: "EUR", to: "USD", rate: "1.5", expected: -2},
+		{input: 10000, from: "USD", to: "JPY", rate: "149.53", expected: 14953},
+		{input: 14953, from: "JPY", to: "USD", rate: "0.0066876", expected: 10000},
+		{input: 1000, from: "KWD", to: "USD", rate: "3.25", expected: 325},
+		{input: 100, from: "USD", to: "KWD", rate: "0.307", expected: 307},
+		{input: 10000, from: "EUR", to: "USD", rate: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s->%s@%s", tt.input, tt.from, tt.to, tt.rate), func(t *testing.T) {
+			rate, _ := new(big.Rat).SetString(tt.rate)
+			converted, err := Convert(tt.input, tt.from, tt.to, rate)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if converted != tt.expected {
+				t.Fatalf("expected value %d, but got value: %d", tt.expected, converted)
+			}
+		})
+	}
+}
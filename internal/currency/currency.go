@@ -4,53 +4,215 @@ package currency
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
-type Currency struct {
-	Name              string `json:"name"`
-	ISOCode           string `json:"iso_code"`
-	Symbol            string `json:"symbol"`
-	DecimalFactor     int64  `json:"decimal_factor"`
-	DecimalSeparator  rune   `json:"decimal_separator"`
-	ThousandSeparator rune   `json:"thousand_separator"`
-	SymbolBefore      bool   `json:"symbol_before"`
-}
+// SignPlacement determines where the negative sign
+// of an amount is placed relative to its symbol.
+type SignPlacement int
+
+const (
+	// SignBeforeSymbol places the sign before everything else, as in -$5.00
+	SignBeforeSymbol SignPlacement = iota
+	// SignAfterSymbol places the sign between the symbol and amount, as in $-5.00
+	SignAfterSymbol
+	// SignAfterAmount places the sign after everything else, as in 5.00$-
+	SignAfterAmount
+)
 
-var Currencies = map[string]Currency{
-	"USD": {"US Dollar", "USD", "$", 100, '.', ',', true},
-	"CAD": {"Canadian Dollar", "CAD", "$", 100, '.', ',', true},
-	"EUR": {"Euro", "EUR", "€", 100, ',', '.', true},
-	"GBP": {"Pound Sterling", "GBP", "£", 100, '.', ',', true},
+type Currency struct {
+	Name              string        `json:"name"`
+	ISOCode           string        `json:"iso_code"`
+	Symbol            string        `json:"symbol"`
+	DecimalFactor     int64         `json:"decimal_factor"`
+	DecimalSeparator  rune          `json:"decimal_separator"`
+	ThousandSeparator rune          `json:"thousand_separator"`
+	SymbolBefore      bool          `json:"symbol_before"`
+	NegativeSign      SignPlacement `json:"negative_sign"`
+	// Grouping lists the sizes of digit groups preceding the decimal
+	// separator, starting nearest to it. The last size repeats, such
+	// that most currencies group by [3], and Indian Rupees by [3, 2].
+	Grouping []int `json:"grouping"`
 }
 
 // NOTE: Further localization of this software may
 // warrant modification of this formatting and
 // parsing logic.
 
+// FormatOptions adjust the way in which an amount is formatted.
+type FormatOptions struct {
+	// UseSymbol includes the currency symbol in the result.
+	UseSymbol bool
+	// Accounting wraps negative amounts in parentheses
+	// rather than marking them with a sign.
+	Accounting bool
+}
+
+// Lookup returns the currency with the given ISO Code, in any case.
+func Lookup(ISOCode string) (Currency, error) {
+	currency, ok := Currencies[strings.ToUpper(ISOCode)]
+	if !ok {
+		return Currency{}, fmt.Errorf("'%s' is not an ISO 4217 currency code", ISOCode)
+	}
+	return currency, nil
+}
+
+// lookupOrDefault returns the currency with the given ISO Code.
+// Unknown codes are treated as a currency with two decimal places,
+// using the code itself as a symbol.
+func lookupOrDefault(ISOCode string) Currency {
+	if currency, err := Lookup(ISOCode); err == nil {
+		return currency
+	}
+	return Currency{
+		Name:              ISOCode,
+		ISOCode:           ISOCode,
+		Symbol:            ISOCode + " ",
+		DecimalFactor:     100,
+		DecimalSeparator:  '.',
+		ThousandSeparator: ',',
+		SymbolBefore:      true,
+		Grouping:          []int{3},
+	}
+}
+
+// Exponent returns the number of digits in the minor unit
+// of the currency, as implied by its decimal factor.
+func (c Currency) Exponent() int {
+	places := 0
+	for factor := c.DecimalFactor; factor >= 10; factor /= 10 {
+		places++
+	}
+	return places
+}
+
 // Format returns a string providing readers with the appropriate
 // currency format corresponding to their currency's ISO Code.
 // It assumes an amount measures in the smallest unit of the
 // currency.
 func Format(amount int64, ISOCode string, useSymbol bool) string {
-	s := strconv.FormatInt(int64(amount), 10)
-	switch len(s) {
-	case 2:
-		s = "0" + s
-	case 1:
-		s = "00" + s
+	return FormatWith(amount, ISOCode, FormatOptions{UseSymbol: useSymbol})
+}
+
+// FormatWith formats an amount measured in the smallest unit of the
+// currency with the given ISO Code, as Format does, according to the
+// given options.
+func FormatWith(amount int64, ISOCode string, opts FormatOptions) string {
+	currency := lookupOrDefault(ISOCode)
+
+	negative := amount < 0
+	// convert to unsigned before negating, so that the
+	// smallest int64 may be represented as well
+	magnitude := uint64(amount)
+	if negative {
+		magnitude = -magnitude
+	}
+
+	factor := uint64(max(currency.DecimalFactor, 1))
+	number := groupDigits(strconv.FormatUint(magnitude/factor, 10), currency.ThousandSeparator, currency.Grouping)
+	if places := currency.Exponent(); places > 0 {
+		minor := strconv.FormatUint(magnitude%factor, 10)
+		number += string(currency.DecimalSeparator) + strings.Repeat("0", places-len(minor)) + minor
+	}
+
+	symbol := ""
+	if opts.UseSymbol {
+		symbol = currency.Symbol
+	}
+	withSymbol := func(number string) string {
+		if currency.SymbolBefore {
+			return symbol + number
+		}
+		return number + symbol
 	}
-	i := len(s) - 2
-	res := s[:i] + string(Currencies[ISOCode].DecimalSeparator) + s[i:]
-	if useSymbol {
-		if Currencies[ISOCode].SymbolBefore {
-			return Currencies[ISOCode].Symbol + res
+
+	switch {
+	case !negative:
+		return withSymbol(number)
+	case opts.Accounting:
+		return "(" + withSymbol(number) + ")"
+	case currency.NegativeSign == SignAfterSymbol:
+		if currency.SymbolBefore {
+			return withSymbol("-" + number)
 		}
-		return res + Currencies[ISOCode].Symbol
+		return "-" + withSymbol(number)
+	case currency.NegativeSign == SignAfterAmount:
+		return withSymbol(number) + "-"
+	default:
+		return "-" + withSymbol(number)
+	}
+}
+
+// groupSize returns the size of the i-th digit group preceding
+// the decimal separator, according to the given grouping.
+func groupSize(grouping []int, i int) int {
+	if len(grouping) == 0 {
+		return 3
+	}
+	return grouping[min(i, len(grouping)-1)]
+}
+
+// groupDigits inserts the separator between digit groups
+// sized according to the grouping, counting from the right.
+func groupDigits(digits string, separator rune, grouping []int) string {
+	if separator == 0 {
+		return digits
+	}
+	groups := []string{}
+	for i := 0; len(digits) > 0; i++ {
+		size := groupSize(grouping, i)
+		if size <= 0 || size >= len(digits) {
+			groups = append(groups, digits)
+			break
+		}
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	slices.Reverse(groups)
+	return strings.Join(groups, string(separator))
+}
+
+// ungroupDigits removes separators from grouped digits, first
+// ensuring that every group is sized according to the grouping.
+func ungroupDigits(grouped string, separator rune, grouping []int) (string, error) {
+	if separator == 0 || !strings.ContainsRune(grouped, separator) {
+		return grouped, nil
+	}
+	groups := strings.Split(grouped, string(separator))
+	for i := range groups {
+		group := groups[len(groups)-1-i]
+		size := groupSize(grouping, i)
+		leftmost := i == len(groups)-1
+		if (leftmost && (len(group) == 0 || len(group) > size)) || (!leftmost && len(group) != size) {
+			return "", fmt.Errorf("improper digit grouping for currency; expected groups like %s", groupDigits("1234567890", separator, grouping))
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// normalizeSeparators replaces characters commonly typed in place of
+// the currency's group separator with the separator itself, such that
+// a regular space may stand for a non-breaking one.
+func normalizeSeparators(s string, currency Currency) string {
+	var alternatives string
+	switch currency.ThousandSeparator {
+	case ' ', '\u00a0', '\u202f':
+		alternatives = " \u00a0\u202f"
+	case '\'', '\u2019':
+		alternatives = "'\u2019"
+	default:
+		return s
 	}
-	// TODO: add thousand separators where necessary
-	return res
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(alternatives, r) {
+			return currency.ThousandSeparator
+		}
+		return r
+	}, s)
 }
 
 // Parse takes a string, attempts to parse it as the
@@ -60,27 +222,34 @@ func Format(amount int64, ISOCode string, useSymbol bool) string {
 // factor necessary to produce the same value in the
 // currency's smallest unit.
 //
+// Parse is lenient toward amounts as they are commonly
+// written or pasted: the currency's symbol or ISO Code may
+// come before or after the amount, a leading plus sign is
+// allowed, parentheses mark a negative amount, fewer
+// decimal places than the currency has may be given, and
+// whitespace may separate digit groups.
+//
 // If the string could could not be parsed as the given
 // currency, an error is returned.
 func Parse(s string, currencyISO string) (int64, error) {
-	currency, ok := Currencies[currencyISO]
+	currency, ok := Currencies[strings.ToUpper(currencyISO)]
 	if !ok {
 		return 0, fmt.Errorf("invalid or unavailable ISO Currency Code")
 	}
+	places := currency.Exponent()
+	s = normalizeSeparators(strings.TrimSpace(s), currency)
 
 	if s == "" {
 		return 0, fmt.Errorf("no content to parse from input")
 	}
 
-	var negateMult int64
-	negateMult = 1
-	if strings.HasPrefix(s, "-") {
-		negateMult = -1
-		s = strings.TrimLeft(s, "-")
+	s, negative, err := stripAffixes(s, currency)
+	if err != nil {
+		return 0, err
 	}
-
-	if strings.HasPrefix(s, "0") {
-		s = strings.TrimLeft(s, "0")
+	s, err = normalizeWhitespaceGroups(s, currency)
+	if err != nil {
+		return 0, err
 	}
 
 	if strings.HasPrefix(s, string(currency.DecimalSeparator)) {
@@ -92,10 +261,20 @@ func Parse(s string, currencyISO string) (int64, error) {
 	pair := strings.Split(s, string(currency.DecimalSeparator))
 	switch len(pair) {
 	case 2:
-		if len(pair[1]) != 2 {
-			return 0, fmt.Errorf("write any specified decimal values to only the hundredths place: .xy")
+		if places == 0 {
+			return 0, fmt.Errorf("%s has no minor unit; write whole amounts only", currency.ISOCode)
 		}
-		parsedCents, err := strconv.ParseInt(pair[1], 10, 64)
+		if strings.ContainsRune(pair[1], currency.ThousandSeparator) {
+			return 0, fmt.Errorf("group separator '%c' found after decimal separator '%c'; write amounts in %s like %s",
+				currency.ThousandSeparator, currency.DecimalSeparator, currency.ISOCode, FormatWith(123450*currency.DecimalFactor/100, currency.ISOCode, FormatOptions{}))
+		}
+		if len(pair[1]) == 0 {
+			return 0, fmt.Errorf("no digits found after decimal separator '%c'", currency.DecimalSeparator)
+		}
+		if len(pair[1]) > places {
+			return 0, fmt.Errorf("write any specified decimal values to at most %d places for %s", places, currency.ISOCode)
+		}
+		parsedCents, err := parseDigits(pair[1] + strings.Repeat("0", places-len(pair[1])))
 		if err != nil {
 			return 0, fmt.Errorf("could not parse cent currency unit: %w", err)
 		}
@@ -104,33 +283,16 @@ func Parse(s string, currencyISO string) (int64, error) {
 	case 1:
 		dollarString := pair[0]
 
-		// let thousand-place separation be optional
-		if strings.Contains(dollarString, string(currency.ThousandSeparator)) {
-			// For the International System of Units, separators for
-			// the portion preceding the decimal should be found
-			// after every third digit.
-			//
-			// WARN: Localization of a currency like Indian Rupees
-			// would demand use of the Indian Numbering System, and
-			// may call this modulo into question.
-
-			if len(dollarString)%4 == 0 {
-				return 0, fmt.Errorf("string could not be parsed as given currency")
-			}
-			// If the dollar amount is 1-999, dollarString may be parsed
-			if len(dollarString) > 3 {
-				dollarRunes := []rune(dollarString)
-				i := 1
-				for j := len(dollarRunes) - 1; j >= 0; j-- {
-					if i%4 == 0 && dollarRunes[j] != currency.ThousandSeparator {
-						return 0, fmt.Errorf("improper thousands separator for currency")
-					}
-					i++
-				}
-				dollarString = strings.ReplaceAll(dollarString, string(currency.ThousandSeparator), "")
+		// let group separation be optional
+		dollarString, err := ungroupDigits(dollarString, currency.ThousandSeparator, currency.Grouping)
+		if err != nil {
+			if len(pair) == 1 && strings.Count(pair[0], string(currency.ThousandSeparator)) == 1 {
+				return 0, fmt.Errorf("'%s' is ambiguous; %s uses '%c' to separate decimals, and '%c' to group digits",
+					s, currency.ISOCode, currency.DecimalSeparator, currency.ThousandSeparator)
 			}
+			return 0, err
 		}
-		parsedDollars, err := strconv.ParseInt(dollarString, 10, 64)
+		parsedDollars, err := parseDigits(dollarString)
 		if err != nil {
 			return 0, fmt.Errorf("could not parse dollar unit: %w", err)
 		}
@@ -141,6 +303,110 @@ func Parse(s string, currencyISO string) (int64, error) {
 	default:
 		return 0, fmt.Errorf("decimal separator found more than once")
 	}
-	total := ((dollars * currency.DecimalFactor) + cents) * negateMult
+	if dollars > (math.MaxInt64-cents)/currency.DecimalFactor {
+		return 0, fmt.Errorf("amount is too large")
+	}
+	total := (dollars * currency.DecimalFactor) + cents
+	if negative {
+		total = -total
+	}
 	return total, nil
 }
+
+// parseDigits parses a string consisting of decimal digits only.
+func parseDigits(digits string) (int64, error) {
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("unexpected '%c'", r)
+		}
+	}
+	return strconv.ParseInt(digits, 10, 64)
+}
+
+// stripAffixes removes the sign, parentheses and currency symbol or
+// ISO Code surrounding an amount, reporting whether it was negative.
+func stripAffixes(s string, currency Currency) (string, bool, error) {
+	negative := false
+	parenthesized := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	if !parenthesized && strings.ContainsAny(s, "()") {
+		if strings.Count(s, "(") != strings.Count(s, ")") {
+			return "", false, fmt.Errorf("unbalanced parentheses in '%s'", s)
+		}
+		return "", false, fmt.Errorf("parentheses must enclose the entire amount to mark it negative")
+	}
+	if parenthesized {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+
+	symbols := []string{currency.ISOCode, strings.ToLower(currency.ISOCode)}
+	if symbol := strings.TrimSpace(currency.Symbol); symbol != "" {
+		symbols = append(symbols, symbol)
+	}
+	cutSymbol := func(cut func(string, string) (string, bool)) bool {
+		for _, symbol := range symbols {
+			if rest, found := cut(s, symbol); found {
+				s = strings.TrimSpace(rest)
+				return true
+			}
+		}
+		return false
+	}
+
+	// a sign and a symbol may lead in either order, as in -$5 or $-5
+	signed, hasSymbol := false, false
+	for {
+		if !hasSymbol && cutSymbol(strings.CutPrefix) {
+			hasSymbol = true
+			continue
+		}
+		if !signed && (strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+")) {
+			signed = true
+			if s[0] == '-' {
+				if negative {
+					return "", false, fmt.Errorf("amount is marked negative by both a sign and parentheses")
+				}
+				negative = true
+			}
+			s = strings.TrimSpace(s[1:])
+			continue
+		}
+		break
+	}
+	if !hasSymbol && cutSymbol(strings.CutSuffix) {
+		hasSymbol = true
+	}
+
+	if s == "" {
+		return "", false, fmt.Errorf("no amount found in input")
+	}
+	runes := []rune(s)
+	for _, r := range []rune{runes[0], runes[len(runes)-1]} {
+		if !unicode.IsDigit(r) && r != currency.DecimalSeparator {
+			if hasSymbol || r == '-' || r == '+' || r == '(' || r == ')' {
+				return "", false, fmt.Errorf("unexpected '%c' in amount", r)
+			}
+			return "", false, fmt.Errorf("unexpected '%c' in amount; amounts in %s may only be marked with '%s' or '%s'",
+				r, currency.ISOCode, strings.TrimSpace(currency.Symbol), currency.ISOCode)
+		}
+	}
+	return s, negative, nil
+}
+
+// normalizeWhitespaceGroups replaces whitespace separating digit groups
+// with the currency's group separator, such that 1 234,50 may be read
+// as 1.234,50 for a currency grouping digits by periods.
+func normalizeWhitespaceGroups(s string, currency Currency) (string, error) {
+	if !strings.ContainsFunc(s, unicode.IsSpace) || unicode.IsSpace(currency.ThousandSeparator) {
+		return s, nil
+	}
+	if strings.ContainsRune(s, currency.ThousandSeparator) {
+		return "", fmt.Errorf("mixed digit group separators in '%s'", s)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return currency.ThousandSeparator
+		}
+		return r
+	}, s), nil
+}
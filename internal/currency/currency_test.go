@@ -2,6 +2,7 @@ package currency
 
 import (
 	"fmt"
+	"math"
 	"testing"
 )
 
@@ -55,6 +56,132 @@ func Test_Format(t *testing.T) {
 			useSymbol: false,
 			expected:  "-25.27",
 		},
+		{
+			input:     0,
+			isoCode:   "USD",
+			useSymbol: true,
+			expected:  "$0.00",
+		},
+		{
+			input:     -5,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "-0.05",
+		},
+		{
+			input:     -50,
+			isoCode:   "USD",
+			useSymbol: true,
+			expected:  "-$0.50",
+		},
+		{
+			input:     -99,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "-0.99",
+		},
+		{
+			input:     100000,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "1,000.00",
+		},
+		{
+			input:     99999,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "999.99",
+		},
+		{
+			input:     123456789,
+			isoCode:   "USD",
+			useSymbol: true,
+			expected:  "$1,234,567.89",
+		},
+		{
+			input:     -123456789,
+			isoCode:   "USD",
+			useSymbol: true,
+			expected:  "-$1,234,567.89",
+		},
+		{
+			input:     123456789,
+			isoCode:   "EUR",
+			useSymbol: false,
+			expected:  "1.234.567,89",
+		},
+		{
+			input:     -5,
+			isoCode:   "EUR",
+			useSymbol: true,
+			expected:  "-€0,05",
+		},
+		{
+			input:     1234500,
+			isoCode:   "GBP",
+			useSymbol: true,
+			expected:  "£12,345.00",
+		},
+		{
+			input:     math.MaxInt64,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "92,233,720,368,547,758.07",
+		},
+		{
+			input:     math.MinInt64,
+			isoCode:   "USD",
+			useSymbol: false,
+			expected:  "-92,233,720,368,547,758.08",
+		},
+		{
+			input:     1234567,
+			isoCode:   "JPY",
+			useSymbol: true,
+			expected:  "¥1,234,567",
+		},
+		{
+			input:     -1234567,
+			isoCode:   "KWD",
+			useSymbol: true,
+			expected:  "-KD 1,234.567",
+		},
+		{
+			input:     123456789,
+			isoCode:   "CHF",
+			useSymbol: true,
+			expected:  "CHF 1'234'567.89",
+		},
+		{
+			input:     123456,
+			isoCode:   "SEK",
+			useSymbol: true,
+			expected:  "1\u00a0234,56 kr",
+		},
+		{
+			input:     1234567890,
+			isoCode:   "INR",
+			useSymbol: true,
+			expected:  "₹1,23,45,678.90",
+		},
+		{
+			input:     -10000000,
+			isoCode:   "INR",
+			useSymbol: true,
+			expected:  "-₹1,00,000.00",
+		},
+		{
+			input:     99900,
+			isoCode:   "INR",
+			useSymbol: false,
+			expected:  "999.00",
+		},
+		{
+			input:     -150,
+			isoCode:   "XYZ",
+			useSymbol: true,
+			expected:  "-XYZ 1.50",
+		},
 	}
 
 	for _, tt := range tests {
@@ -67,6 +194,121 @@ func Test_Format(t *testing.T) {
 	}
 }
 
+func Test_FormatWith(t *testing.T) {
+	Currencies["TSA"] = Currency{"Test Sign After Symbol", "TSA", "$", 100, '.', ',', true, SignAfterSymbol, []int{3}}
+	Currencies["TSN"] = Currency{"Test Sign After Amount", "TSN", "kr", 100, ',', ' ', false, SignAfterAmount, []int{3}}
+	Currencies["TSX"] = Currency{"Test Symbol After", "TSX", "€", 100, ',', '.', false, SignAfterSymbol, []int{3}}
+	Currencies["TZD"] = Currency{"Test Zero Decimals", "TZD", "¥", 1, '.', ',', true, SignBeforeSymbol, []int{3}}
+	Currencies["TTD"] = Currency{"Test Three Decimals", "TTD", "KD ", 1000, '.', ',', true, SignBeforeSymbol, []int{3}}
+	Currencies["TNS"] = Currency{"Test No Separator", "TNS", "$", 100, '.', 0, true, SignBeforeSymbol, []int{3}}
+	defer func() {
+		for _, code := range []string{"TSA", "TSN", "TSX", "TZD", "TTD", "TNS"} {
+			delete(Currencies, code)
+		}
+	}()
+
+	tests := []struct {
+		isoCode  string
+		opts     FormatOptions
+		expected string
+		input    int64
+	}{
+		{
+			input:    -123456,
+			isoCode:  "USD",
+			opts:     FormatOptions{UseSymbol: true, Accounting: true},
+			expected: "($1,234.56)",
+		},
+		{
+			input:    -5,
+			isoCode:  "USD",
+			opts:     FormatOptions{Accounting: true},
+			expected: "(0.05)",
+		},
+		{
+			input:    123456,
+			isoCode:  "USD",
+			opts:     FormatOptions{UseSymbol: true, Accounting: true},
+			expected: "$1,234.56",
+		},
+		{
+			input:    -123456,
+			isoCode:  "TSA",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "$-1,234.56",
+		},
+		{
+			input:    -123456,
+			isoCode:  "TSA",
+			opts:     FormatOptions{UseSymbol: false},
+			expected: "-1,234.56",
+		},
+		{
+			input:    -123456,
+			isoCode:  "TSN",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "1 234,56kr-",
+		},
+		{
+			input:    -123456,
+			isoCode:  "TSN",
+			opts:     FormatOptions{UseSymbol: true, Accounting: true},
+			expected: "(1 234,56kr)",
+		},
+		{
+			input:    -123456,
+			isoCode:  "TSX",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "-1.234,56€",
+		},
+		{
+			input:    1234,
+			isoCode:  "TSX",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "12,34€",
+		},
+		{
+			input:    -1234567,
+			isoCode:  "TZD",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "-¥1,234,567",
+		},
+		{
+			input:    0,
+			isoCode:  "TZD",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "¥0",
+		},
+		{
+			input:    -5,
+			isoCode:  "TTD",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "-KD 0.005",
+		},
+		{
+			input:    1234567,
+			isoCode:  "TTD",
+			opts:     FormatOptions{UseSymbol: false},
+			expected: "1,234.567",
+		},
+		{
+			input:    123456789,
+			isoCode:  "TNS",
+			opts:     FormatOptions{UseSymbol: true},
+			expected: "$1234567.89",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s: %d %+v", tt.isoCode, tt.input, tt.opts), func(t *testing.T) {
+			displayStr := FormatWith(tt.input, tt.isoCode, tt.opts)
+			if displayStr != tt.expected {
+				t.Fatalf("expected string %s, but got string: %s", tt.expected, displayStr)
+			}
+		})
+	}
+}
+
 func Test_ParseCurrencyFromString(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -156,6 +398,273 @@ func Test_ParseCurrencyFromString(t *testing.T) {
 			isoCode:  "EUR",
 			expected: 50139,
 		},
+		{
+			input:    "1,500",
+			isoCode:  "JPY",
+			expected: 1500,
+		},
+		{
+			input:    "1500.00",
+			isoCode:  "JPY",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    "12.345",
+			isoCode:  "KWD",
+			expected: 12345,
+		},
+		{
+			input:    "12.34",
+			isoCode:  "KWD",
+			expected: 12340,
+		},
+		{
+			input:    "12.3456",
+			isoCode:  "KWD",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    "0.005",
+			isoCode:  "BHD",
+			expected: 5,
+		},
+		{
+			input:    "1'234.50",
+			isoCode:  "CHF",
+			expected: 123450,
+		},
+		{
+			input:    "1 234,50",
+			isoCode:  "SEK",
+			expected: 123450,
+		},
+		{
+			input:    "1,23,45,678.90",
+			isoCode:  "INR",
+			expected: 1234567890,
+		},
+		{
+			input:    "1,00,000",
+			isoCode:  "INR",
+			expected: 10000000,
+		},
+		{
+			input:    "12345678.90",
+			isoCode:  "INR",
+			expected: 1234567890,
+		},
+		{
+			input:    "1,234,567.89",
+			isoCode:  "INR",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    "1,23,456.00",
+			isoCode:  "USD",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    "2,00",
+			isoCode:  "USD",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    ",200",
+			isoCode:  "USD",
+			expected: 0,
+			wantErr:  true,
+		},
+		{
+			input:    "1\u00a0234,50",
+			isoCode:  "SEK",
+			expected: 123450,
+		},
+		{
+			input:    "1\u202f234\u00a0567,50",
+			isoCode:  "SEK",
+			expected: 123456750,
+		},
+		{
+			input:    "1\u2019234.50",
+			isoCode:  "CHF",
+			expected: 123450,
+		},
+		{
+			input:    "$1,234.50",
+			isoCode:  "USD",
+			expected: 123450,
+		},
+		{
+			input:    "-$1,234.50",
+			isoCode:  "USD",
+			expected: -123450,
+		},
+		{
+			input:    "$-1,234.50",
+			isoCode:  "USD",
+			expected: -123450,
+		},
+		{
+			input:    "1,234.50 USD",
+			isoCode:  "USD",
+			expected: 123450,
+		},
+		{
+			input:    "usd 12",
+			isoCode:  "USD",
+			expected: 1200,
+		},
+		{
+			input:    "(12.00)",
+			isoCode:  "USD",
+			expected: -1200,
+		},
+		{
+			input:    "($12.00)",
+			isoCode:  "USD",
+			expected: -1200,
+		},
+		{
+			input:    "+15",
+			isoCode:  "USD",
+			expected: 1500,
+		},
+		{
+			input:    "12.5",
+			isoCode:  "USD",
+			expected: 1250,
+		},
+		{
+			input:    "-.5",
+			isoCode:  "USD",
+			expected: -50,
+		},
+		{
+			input:    "0",
+			isoCode:  "USD",
+			expected: 0,
+		},
+		{
+			input:    "007.00",
+			isoCode:  "USD",
+			expected: 700,
+		},
+		{
+			input:    "  42  ",
+			isoCode:  "USD",
+			expected: 4200,
+		},
+		{
+			input:    "1 234.50",
+			isoCode:  "USD",
+			expected: 123450,
+		},
+		{
+			input:    "1 234,50 €",
+			isoCode:  "EUR",
+			expected: 123450,
+		},
+		{
+			input:    "€1.234,50",
+			isoCode:  "EUR",
+			expected: 123450,
+		},
+		{
+			input:    "1\u00a0234,50 kr",
+			isoCode:  "SEK",
+			expected: 123450,
+		},
+		{
+			input:    "CHF 1'234.50",
+			isoCode:  "CHF",
+			expected: 123450,
+		},
+		{
+			input:    "¥1,500",
+			isoCode:  "JPY",
+			expected: 1500,
+		},
+		{
+			input:   "1.5",
+			isoCode: "EUR",
+			wantErr: true,
+		},
+		{
+			input:   "1,234.50",
+			isoCode: "EUR",
+			wantErr: true,
+		},
+		{
+			input:   "1 234,567.50",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "€12.00",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "-(12.00)",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "(-12.00)",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "(12.00",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "--12",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "12-",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "$",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "12.",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "1a2",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "99999999999999999.99",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:    "10.50",
+			isoCode:  "usd",
+			expected: 1050,
+		},
+		{
+			input:    "10.00",
+			isoCode:  "XYZ",
+			expected: 0,
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -170,3 +679,77 @@ func Test_ParseCurrencyFromString(t *testing.T) {
 		})
 	}
 }
+
+func Test_GroupDigits(t *testing.T) {
+	tests := []struct {
+		digits    string
+		separator rune
+		grouping  []int
+		expected  string
+	}{
+		{digits: "1", separator: ',', grouping: []int{3}, expected: "1"},
+		{digits: "123", separator: ',', grouping: []int{3}, expected: "123"},
+		{digits: "1234", separator: ',', grouping: []int{3}, expected: "1,234"},
+		{digits: "123456", separator: ',', grouping: []int{3}, expected: "123,456"},
+		{digits: "1234567", separator: '\'', grouping: []int{3}, expected: "1'234'567"},
+		{digits: "1234", separator: ',', grouping: []int{3, 2}, expected: "1,234"},
+		{digits: "12345", separator: ',', grouping: []int{3, 2}, expected: "12,345"},
+		{digits: "123456", separator: ',', grouping: []int{3, 2}, expected: "1,23,456"},
+		{digits: "123456789", separator: ',', grouping: []int{3, 2}, expected: "12,34,56,789"},
+		{digits: "123456789", separator: ',', grouping: []int{4}, expected: "1,2345,6789"},
+		{digits: "123456789", separator: ',', grouping: nil, expected: "123,456,789"},
+		{digits: "123456789", separator: 0, grouping: []int{3}, expected: "123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s: %v", tt.digits, tt.grouping), func(t *testing.T) {
+			grouped := groupDigits(tt.digits, tt.separator, tt.grouping)
+			if grouped != tt.expected {
+				t.Fatalf("expected string %s, but got string: %s", tt.expected, grouped)
+			}
+			ungrouped, err := ungroupDigits(grouped, tt.separator, tt.grouping)
+			if err != nil || ungrouped != tt.digits {
+				t.Fatalf("expected to ungroup %s back to %s, but got %s, with err value: %v", grouped, tt.digits, ungrouped, err)
+			}
+		})
+	}
+}
+
+func Test_Currencies(t *testing.T) {
+	if len(Currencies) < 150 {
+		t.Fatalf("expected a full ISO 4217 table, but found only %d currencies", len(Currencies))
+	}
+	for code, currency := range Currencies {
+		if code != currency.ISOCode || len(code) != 3 {
+			t.Errorf("currency keyed by %s has ISO Code %s", code, currency.ISOCode)
+		}
+		if currency.Name == "" || currency.Symbol == "" {
+			t.Errorf("%s: missing name or symbol", code)
+		}
+		if currency.DecimalSeparator == currency.ThousandSeparator {
+			t.Errorf("%s: decimal and group separators are both '%c'", code, currency.DecimalSeparator)
+		}
+		if len(currency.Grouping) == 0 {
+			t.Errorf("%s: missing grouping", code)
+		}
+		if exponent := currency.Exponent(); exponent > 4 {
+			t.Errorf("%s: unexpected exponent %d", code, exponent)
+		}
+	}
+
+	exponents := map[string]int{"USD": 2, "JPY": 0, "KRW": 0, "KWD": 3, "BHD": 3, "CLF": 4}
+	for code, expected := range exponents {
+		if got := Currencies[code].Exponent(); got != expected {
+			t.Errorf("%s: expected exponent %d, but got %d", code, expected, got)
+		}
+	}
+}
+
+func Test_Lookup(t *testing.T) {
+	if currency, err := Lookup("jpy"); err != nil || currency.ISOCode != "JPY" {
+		t.Fatalf("expected to find JPY, but got %v, with err value: %v", currency.ISOCode, err)
+	}
+	if _, err := Lookup("ABC"); err == nil {
+		t.Fatalf("expected error for unknown ISO Code")
+	}
+}
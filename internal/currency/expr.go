@@ -0,0 +1,269 @@
+package currency
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// operators are the characters which, outside of a number,
+// mark input as an arithmetic expression.
+const operators = "+-*/%()"
+
+// Evaluate returns the amount that the given input stands for, in the
+// smallest unit of the currency with the given ISO Code. The input may
+// be an amount as accepted by Parse, or an arithmetic expression, such
+// as 84.20/3+5. Input beginning with '=' is always treated as an
+// expression.
+//
+// Expressions support +, -, *, / and parentheses, as well as % as a
+// postfix operator dividing its operand by 100, such that 80*15% is 12.
+// Numbers within an expression are written with the decimal and group
+// separators of the currency, and may have any number of decimal places.
+//
+// Intermediate values are exact. Only the result is rounded, to the
+// nearest minor unit of the currency, with halves rounded away from zero.
+func Evaluate(s string, ISOCode string) (int64, error) {
+	expr, isExpr := strings.CutPrefix(strings.TrimSpace(s), "=")
+	if !isExpr {
+		amount, err := Parse(s, ISOCode)
+		if err == nil || !strings.ContainsAny(strings.TrimPrefix(s, "-"), operators) {
+			return amount, err
+		}
+	}
+
+	currency, ok := Currencies[ISOCode]
+	if !ok {
+		return 0, fmt.Errorf("invalid or unavailable ISO Currency Code")
+	}
+	p := &exprParser{input: []rune(normalizeSeparators(expr, currency)), currency: currency}
+	value, err := p.parse()
+	if err != nil {
+		return 0, fmt.Errorf("could not evaluate '%s': %w", expr, err)
+	}
+	amount, err := roundToMinorUnit(value, currency.DecimalFactor)
+	if err != nil {
+		return 0, fmt.Errorf("could not evaluate '%s': %w", expr, err)
+	}
+	return amount, nil
+}
+
+// roundToMinorUnit converts a value in the major unit of a currency
+// to its minor unit, rounding halves away from zero.
+func roundToMinorUnit(value *big.Rat, factor int64) (int64, error) {
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt64(factor))
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	// compare twice the remainder against the denominator
+	// to determine whether it amounts to at least a half
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(scaled.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(scaled.Sign())))
+	}
+	if !quotient.IsInt64() || quotient.Int64() == math.MinInt64 {
+		return 0, fmt.Errorf("result is too large")
+	}
+	return quotient.Int64(), nil
+}
+
+// exprParser evaluates arithmetic expressions by recursive descent.
+type exprParser struct {
+	input    []rune
+	pos      int
+	currency Currency
+}
+
+func (p *exprParser) parse() (*big.Rat, error) {
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return nil, fmt.Errorf("no content to evaluate")
+	}
+	value, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected '%c'", p.input[p.pos])
+	}
+	return value, nil
+}
+
+// parseSum parses terms joined by + or -.
+func (p *exprParser) parseSum() (*big.Rat, error) {
+	value, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.next()
+			term, err := p.parseProduct()
+			if err != nil {
+				return nil, err
+			}
+			value.Add(value, term)
+		case '-':
+			p.next()
+			term, err := p.parseProduct()
+			if err != nil {
+				return nil, err
+			}
+			value.Sub(value, term)
+		default:
+			return value, nil
+		}
+	}
+}
+
+// parseProduct parses factors joined by * or /.
+func (p *exprParser) parseProduct() (*big.Rat, error) {
+	value, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case '*':
+			p.next()
+			factor, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			value.Mul(value, factor)
+		case '/':
+			p.next()
+			at := p.pos
+			divisor, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			if divisor.Sign() == 0 {
+				p.pos = at
+				return nil, p.errorf("division by zero")
+			}
+			value.Quo(value, divisor)
+		default:
+			return value, nil
+		}
+	}
+}
+
+// parseUnary parses a factor preceded by any number of signs.
+func (p *exprParser) parseUnary() (*big.Rat, error) {
+	switch p.peek() {
+	case '-':
+		p.next()
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return value.Neg(value), nil
+	case '+':
+		p.next()
+		return p.parseUnary()
+	default:
+		return p.parsePercent()
+	}
+}
+
+// parsePercent parses a primary followed by any number of %.
+func (p *exprParser) parsePercent() (*big.Rat, error) {
+	value, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '%' {
+		p.next()
+		value.Quo(value, big.NewRat(100, 1))
+	}
+	return value, nil
+}
+
+// parsePrimary parses a number, or an expression in parentheses.
+func (p *exprParser) parsePrimary() (*big.Rat, error) {
+	switch r := p.peek(); {
+	case r == '(':
+		p.next()
+		value, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.next()
+		return value, nil
+	case r == 0:
+		return nil, p.errorf("expected a number, but the expression ended")
+	case !p.isNumberRune(r):
+		return nil, p.errorf("expected a number, but found '%c'", r)
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && p.isNumberRune(p.input[p.pos]) {
+		p.pos++
+		// a whitespace group separator belongs to the
+		// number only if another digit follows it
+		if p.pos+1 < len(p.input) && p.input[p.pos] == p.currency.ThousandSeparator &&
+			unicode.IsSpace(p.input[p.pos]) && unicode.IsDigit(p.input[p.pos+1]) {
+			p.pos++
+		}
+	}
+	literal := string(p.input[start:p.pos])
+	p.skipSpace()
+
+	whole, fraction, _ := strings.Cut(literal, string(p.currency.DecimalSeparator))
+	if strings.ContainsRune(fraction, p.currency.DecimalSeparator) {
+		p.pos = start
+		return nil, p.errorf("decimal separator found more than once in '%s'", literal)
+	}
+	whole, err := ungroupDigits(whole, p.currency.ThousandSeparator, p.currency.Grouping)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("%v", err)
+	}
+	if strings.ContainsRune(fraction, p.currency.ThousandSeparator) {
+		p.pos = start
+		return nil, p.errorf("group separator found after decimal separator in '%s'", literal)
+	}
+	value, ok := new(big.Rat).SetString("0" + whole + "." + fraction + "0")
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("could not read number '%s'", literal)
+	}
+	return value, nil
+}
+
+// isNumberRune reports whether the rune may begin or end a number.
+// Whitespace group separators are excluded, being indistinguishable
+// from whitespace between tokens.
+func (p *exprParser) isNumberRune(r rune) bool {
+	return unicode.IsDigit(r) || r == p.currency.DecimalSeparator ||
+		(r == p.currency.ThousandSeparator && !unicode.IsSpace(r))
+}
+
+// peek returns the next rune of input, or 0 at its end.
+func (p *exprParser) peek() rune {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// next consumes the next rune of input and any whitespace following it.
+func (p *exprParser) next() {
+	p.pos++
+	p.skipSpace()
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) errorf(format string, a ...any) error {
+	return fmt.Errorf("at position %d: %s", p.pos+1, fmt.Sprintf(format, a...))
+}
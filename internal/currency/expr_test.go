@@ -0,0 +1,239 @@
+package currency
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_Evaluate(t *testing.T) {
+	tests := []struct {
+		input    string
+		isoCode  string
+		expected int64
+		wantErr  bool
+	}{
+		{
+			input:    "200.00",
+			isoCode:  "USD",
+			expected: 20000,
+		},
+		{
+			input:    "-5.27",
+			isoCode:  "USD",
+			expected: -527,
+		},
+		{
+			input:    "2,000.00",
+			isoCode:  "USD",
+			expected: 200000,
+		},
+		{
+			input:    "=84.20/3+5",
+			isoCode:  "USD",
+			expected: 3307,
+		},
+		{
+			input:    "84.20/3+5",
+			isoCode:  "USD",
+			expected: 3307,
+		},
+		{
+			input:    "45*4",
+			isoCode:  "USD",
+			expected: 18000,
+		},
+		{
+			input:    "= 45 * 4 ",
+			isoCode:  "USD",
+			expected: 18000,
+		},
+		{
+			input:    "=1+2*3",
+			isoCode:  "USD",
+			expected: 700,
+		},
+		{
+			input:    "=(1+2)*3",
+			isoCode:  "USD",
+			expected: 900,
+		},
+		{
+			input:    "=10-2-3",
+			isoCode:  "USD",
+			expected: 500,
+		},
+		{
+			input:    "=12/2/3",
+			isoCode:  "USD",
+			expected: 200,
+		},
+		{
+			input:    "=-(5+5)",
+			isoCode:  "USD",
+			expected: -1000,
+		},
+		{
+			input:    "-5*2",
+			isoCode:  "USD",
+			expected: -1000,
+		},
+		{
+			input:    "=80*15%",
+			isoCode:  "USD",
+			expected: 1200,
+		},
+		{
+			input:    "=42.50*(1+18%)",
+			isoCode:  "USD",
+			expected: 5015,
+		},
+		{
+			input:    "=1,234.50*2",
+			isoCode:  "USD",
+			expected: 246900,
+		},
+		{
+			input:    "=.5*3",
+			isoCode:  "USD",
+			expected: 150,
+		},
+		{
+			// 0.125 rounds away from zero
+			input:    "=1/8",
+			isoCode:  "USD",
+			expected: 13,
+		},
+		{
+			input:    "=-1/8",
+			isoCode:  "USD",
+			expected: -13,
+		},
+		{
+			input:    "=1/3",
+			isoCode:  "USD",
+			expected: 33,
+		},
+		{
+			input:    "=2/3",
+			isoCode:  "USD",
+			expected: 67,
+		},
+		{
+			input:    "=1/3*3",
+			isoCode:  "USD",
+			expected: 100,
+		},
+		{
+			input:    "=1000/3",
+			isoCode:  "JPY",
+			expected: 333,
+		},
+		{
+			input:    "=1000*0.5",
+			isoCode:  "JPY",
+			expected: 500,
+		},
+		{
+			input:    "=10/3",
+			isoCode:  "KWD",
+			expected: 3333,
+		},
+		{
+			input:    "=12,50*2",
+			isoCode:  "EUR",
+			expected: 2500,
+		},
+		{
+			input:    "=1.000,50+1",
+			isoCode:  "EUR",
+			expected: 100150,
+		},
+		{
+			input:    "=1 000,50 + 1",
+			isoCode:  "SEK",
+			expected: 100150,
+		},
+		{
+			input:    "=1,00,000/2",
+			isoCode:  "INR",
+			expected: 5000000,
+		},
+		{
+			input:   "=",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5/0",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5/(2-2)",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=(5+5",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5+5)",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5+",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5 5",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5.0.0",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=12,34*2",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5x2",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=99999999999999999999",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "55.555",
+			isoCode: "USD",
+			wantErr: true,
+		},
+		{
+			input:   "=5*2",
+			isoCode: "XYZ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s: %s", tt.isoCode, tt.input), func(t *testing.T) {
+			amount, err := Evaluate(tt.input, tt.isoCode)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if amount != tt.expected {
+				t.Fatalf("expected value %d, but got value: %d", tt.expected, amount)
+			}
+		})
+	}
+}
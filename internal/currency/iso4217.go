@@ -0,0 +1,100 @@
+package currency
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed iso4217.csv
+var iso4217 string
+
+// Currencies holds every currency of ISO 4217 known to the
+// CLI, keyed by ISO Code.
+var Currencies = mustParseCurrencies(iso4217)
+
+// mustParseCurrencies reads a table of currencies as found in
+// iso4217.csv. As the table is embedded within the binary, any
+// failure to read it is a programming error, and panics.
+func mustParseCurrencies(table string) map[string]Currency {
+	reader := csv.NewReader(strings.NewReader(table))
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("currency: could not read ISO 4217 table: %v", err))
+	}
+
+	currencies := make(map[string]Currency, len(records))
+	// skip the header
+	for _, record := range records[1:] {
+		currency, err := parseCurrencyRecord(record)
+		if err != nil {
+			panic(fmt.Sprintf("currency: bad ISO 4217 table entry %v: %v", record, err))
+		}
+		currencies[currency.ISOCode] = currency
+	}
+	return currencies
+}
+
+func parseCurrencyRecord(record []string) (Currency, error) {
+	code, name, symbol := record[0], record[1], record[2]
+	exponent, err := strconv.Atoi(record[3])
+	if err != nil || exponent < 0 {
+		return Currency{}, fmt.Errorf("bad exponent '%s'", record[3])
+	}
+	decimalSeparator, err := parseSeparator(record[4])
+	if err != nil {
+		return Currency{}, err
+	}
+	groupSeparator, err := parseSeparator(record[5])
+	if err != nil {
+		return Currency{}, err
+	}
+	grouping := []int{}
+	for size := range strings.SplitSeq(record[6], ";") {
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 {
+			return Currency{}, fmt.Errorf("bad grouping '%s'", record[6])
+		}
+		grouping = append(grouping, n)
+	}
+	symbolBefore, err := strconv.ParseBool(record[7])
+	if err != nil {
+		return Currency{}, err
+	}
+
+	factor := int64(1)
+	for range exponent {
+		factor *= 10
+	}
+	return Currency{
+		Name:              name,
+		ISOCode:           code,
+		Symbol:            symbol,
+		DecimalFactor:     factor,
+		DecimalSeparator:  decimalSeparator,
+		ThousandSeparator: groupSeparator,
+		SymbolBefore:      symbolBefore,
+		Grouping:          grouping,
+	}, nil
+}
+
+// parseSeparator reads a separator written either as the
+// character itself, or as its code point, as in U+00A0.
+func parseSeparator(field string) (rune, error) {
+	if codePoint, ok := strings.CutPrefix(field, "U+"); ok {
+		r, err := strconv.ParseUint(codePoint, 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("bad code point '%s'", field)
+		}
+		return rune(r), nil
+	}
+	if utf8.RuneCountInString(field) != 1 {
+		return 0, fmt.Errorf("separator '%s' must be a single character", field)
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return r, nil
+}
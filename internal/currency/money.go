@@ -0,0 +1,194 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Money is an amount in the smallest unit of a currency, such as cents,
+// together with the ISO Code of that currency. Arithmetic on Money
+// fails rather than wrapping around, and refuses to mix currencies.
+type Money struct {
+	Amount  int64  `json:"amount"`
+	ISOCode string `json:"currency"`
+}
+
+// NewMoney returns the given amount of the currency with the given ISO Code.
+func NewMoney(amount int64, ISOCode string) Money {
+	return Money{Amount: amount, ISOCode: ISOCode}
+}
+
+// EvaluateMoney returns the Money that the given input stands for,
+// as read by Evaluate in the currency with the given ISO Code.
+func EvaluateMoney(s string, ISOCode string) (Money, error) {
+	amount, err := Evaluate(s, ISOCode)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, ISOCode), nil
+}
+
+// Add returns the sum of m and other.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	// the sum of two numbers of the same sign never changes sign
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, m.overflow("adding", other)
+	}
+	return NewMoney(sum, m.ISOCode), nil
+}
+
+// Sub returns the difference of m and other.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	difference := m.Amount - other.Amount
+	if (other.Amount > 0 && difference > m.Amount) || (other.Amount < 0 && difference < m.Amount) {
+		return Money{}, m.overflow("subtracting", other)
+	}
+	return NewMoney(difference, m.ISOCode), nil
+}
+
+// Neg returns m with its sign reversed.
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("negating %s is out of range", m)
+	}
+	return NewMoney(-m.Amount, m.ISOCode), nil
+}
+
+// Mul returns m multiplied by the given factor.
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.Amount == 0 || factor == 0 {
+		return NewMoney(0, m.ISOCode), nil
+	}
+	product := m.Amount * factor
+	if product/factor != m.Amount || (m.Amount == -1 && factor == math.MinInt64) ||
+		(factor == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("multiplying %s by %d is out of range", m, factor)
+	}
+	return NewMoney(product, m.ISOCode), nil
+}
+
+// Allocate splits m into the given number of parts, as near to equal
+// as the smallest unit of its currency allows. Whatever cannot be
+// divided evenly is handed out one unit at a time, starting with the
+// first part, such that the parts always add up to m.
+func (m Money) Allocate(parts int) ([]Money, error) {
+	if parts <= 0 {
+		return nil, fmt.Errorf("cannot allocate %s into %d parts", m, parts)
+	}
+	share := m.Amount / int64(parts)
+	// the remainder shares the sign of the amount
+	remainder := m.Amount % int64(parts)
+	unit := int64(1)
+	if remainder < 0 {
+		unit, remainder = -1, -remainder
+	}
+	allocated := make([]Money, parts)
+	for i := range allocated {
+		allocated[i] = NewMoney(share, m.ISOCode)
+		if int64(i) < remainder {
+			allocated[i].Amount += unit
+		}
+	}
+	return allocated, nil
+}
+
+// Cmp compares m with other, returning -1 if m is less,
+// 0 if they are equal, and +1 if m is greater.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal reports whether m and other are the same amount of the same currency.
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && m.ISOCode == other.ISOCode
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+
+// String returns m formatted with the symbol of its currency, as in $1,234.50.
+func (m Money) String() string {
+	return Format(m.Amount, m.ISOCode, true)
+}
+
+// Format implements fmt.Formatter. The verbs %v and %s format m with
+// the symbol of its currency, and %q does the same in quotes. The +
+// flag, as in %+v, formats m without the symbol but followed by its
+// ISO Code instead, as in 1,234.50 USD. The verb %d gives the amount
+// in the smallest unit of the currency.
+func (m Money) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'v', 's', 'q':
+		if f.Flag('+') {
+			s = Format(m.Amount, m.ISOCode, false) + " " + m.ISOCode
+		} else {
+			s = m.String()
+		}
+		if verb == 'q' {
+			s = strconv.Quote(s)
+		}
+	case 'd':
+		s = strconv.FormatInt(m.Amount, 10)
+	default:
+		s = fmt.Sprintf("%%!%c(currency.Money=%s)", verb, m)
+	}
+	if width, ok := f.Width(); ok && len([]rune(s)) < width {
+		padding := fmt.Sprintf("%*s", width-len([]rune(s)), "")
+		if f.Flag('-') {
+			s += padding
+		} else {
+			s = padding + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// UnmarshalJSON reads Money as written by json.Marshal,
+// rejecting currencies which are not in ISO 4217.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	// an alias type drops this method, avoiding recursion
+	type money Money
+	var decoded money
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+	currency, err := Lookup(decoded.ISOCode)
+	if err != nil {
+		return err
+	}
+	*m = NewMoney(decoded.Amount, currency.ISOCode)
+	return nil
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.ISOCode != other.ISOCode {
+		return fmt.Errorf("cannot combine amounts of %s and %s", m.ISOCode, other.ISOCode)
+	}
+	return nil
+}
+
+func (m Money) overflow(operation string, other Money) error {
+	return fmt.Errorf("%s %s and %s is out of range", operation, m, other)
+}
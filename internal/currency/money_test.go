@@ -0,0 +1,214 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func Test_MoneyArithmetic(t *testing.T) {
+	usd := func(amount int64) Money { return NewMoney(amount, "USD") }
+	tests := []struct {
+		name     string
+		op       func() (Money, error)
+		expected Money
+		wantErr  bool
+	}{
+		{
+			name:     "add",
+			op:       func() (Money, error) { return usd(1050).Add(usd(-300)) },
+			expected: usd(750),
+		},
+		{
+			name:    "add overflow",
+			op:      func() (Money, error) { return usd(math.MaxInt64).Add(usd(1)) },
+			wantErr: true,
+		},
+		{
+			name:    "add underflow",
+			op:      func() (Money, error) { return usd(math.MinInt64).Add(usd(-1)) },
+			wantErr: true,
+		},
+		{
+			name:    "add mixed currencies",
+			op:      func() (Money, error) { return usd(100).Add(NewMoney(100, "EUR")) },
+			wantErr: true,
+		},
+		{
+			name:     "sub",
+			op:       func() (Money, error) { return usd(300).Sub(usd(1050)) },
+			expected: usd(-750),
+		},
+		{
+			name:    "sub overflow",
+			op:      func() (Money, error) { return usd(math.MaxInt64).Sub(usd(-1)) },
+			wantErr: true,
+		},
+		{
+			name:    "sub underflow",
+			op:      func() (Money, error) { return usd(math.MinInt64).Sub(usd(1)) },
+			wantErr: true,
+		},
+		{
+			name:     "neg",
+			op:       func() (Money, error) { return usd(527).Neg() },
+			expected: usd(-527),
+		},
+		{
+			name:    "neg of minimum",
+			op:      func() (Money, error) { return usd(math.MinInt64).Neg() },
+			wantErr: true,
+		},
+		{
+			name:     "mul",
+			op:       func() (Money, error) { return usd(-250).Mul(4) },
+			expected: usd(-1000),
+		},
+		{
+			name:     "mul by zero",
+			op:       func() (Money, error) { return usd(math.MinInt64).Mul(0) },
+			expected: usd(0),
+		},
+		{
+			name:    "mul overflow",
+			op:      func() (Money, error) { return usd(math.MaxInt64 / 2).Mul(3) },
+			wantErr: true,
+		},
+		{
+			name:    "mul minimum by -1",
+			op:      func() (Money, error) { return usd(math.MinInt64).Mul(-1) },
+			wantErr: true,
+		},
+		{
+			name:    "mul -1 by minimum",
+			op:      func() (Money, error) { return usd(-1).Mul(math.MinInt64) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.op()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if !result.Equal(tt.expected) && !tt.wantErr {
+				t.Fatalf("expected %+v, but got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_MoneyAllocate(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		parts    int
+		expected []int64
+		wantErr  bool
+	}{
+		{amount: 1000, parts: 3, expected: []int64{334, 333, 333}},
+		{amount: 1001, parts: 3, expected: []int64{334, 334, 333}},
+		{amount: -1000, parts: 3, expected: []int64{-334, -333, -333}},
+		{amount: 2, parts: 4, expected: []int64{1, 1, 0, 0}},
+		{amount: 900, parts: 1, expected: []int64{900}},
+		{amount: 0, parts: 2, expected: []int64{0, 0}},
+		{amount: math.MinInt64, parts: 2, expected: []int64{math.MinInt64 / 2, math.MinInt64 / 2}},
+		{amount: 100, parts: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%d", tt.amount, tt.parts), func(t *testing.T) {
+			allocated, err := NewMoney(tt.amount, "USD").Allocate(tt.parts)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			amounts := []int64{}
+			for _, part := range allocated {
+				if part.ISOCode != "USD" {
+					t.Fatalf("expected parts in USD, but got %s", part.ISOCode)
+				}
+				amounts = append(amounts, part.Amount)
+			}
+			if !slices.Equal(amounts, tt.expected) && !tt.wantErr {
+				t.Fatalf("expected parts %v, but got %v", tt.expected, amounts)
+			}
+		})
+	}
+}
+
+func Test_MoneyCmp(t *testing.T) {
+	tests := []struct {
+		a, b     Money
+		expected int
+		wantErr  bool
+	}{
+		{a: NewMoney(100, "USD"), b: NewMoney(200, "USD"), expected: -1},
+		{a: NewMoney(200, "USD"), b: NewMoney(200, "USD"), expected: 0},
+		{a: NewMoney(-100, "USD"), b: NewMoney(-200, "USD"), expected: 1},
+		{a: NewMoney(100, "USD"), b: NewMoney(100, "EUR"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%+v vs %+v", tt.a, tt.b), func(t *testing.T) {
+			result, err := tt.a.Cmp(tt.b)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if result != tt.expected {
+				t.Fatalf("expected %d, but got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_MoneyFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		money    Money
+		expected string
+	}{
+		{format: "%v", money: NewMoney(123450, "USD"), expected: "$1,234.50"},
+		{format: "%s", money: NewMoney(-527, "USD"), expected: "-$5.27"},
+		{format: "%+v", money: NewMoney(123450, "USD"), expected: "1,234.50 USD"},
+		{format: "%d", money: NewMoney(-527, "USD"), expected: "-527"},
+		{format: "%q", money: NewMoney(500, "JPY"), expected: `"¥500"`},
+		{format: "%8v|", money: NewMoney(500, "USD"), expected: "   $5.00|"},
+		{format: "%-8v|", money: NewMoney(500, "USD"), expected: "$5.00   |"},
+		{format: "%x", money: NewMoney(500, "USD"), expected: "%!x(currency.Money=$5.00)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			result := fmt.Sprintf(tt.format, tt.money)
+			if result != tt.expected {
+				t.Fatalf("expected %q, but got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_MoneyJSON(t *testing.T) {
+	data, err := json.Marshal(NewMoney(-527, "USD"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"amount":-527,"currency":"USD"}` {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+
+	var decoded Money
+	err = json.Unmarshal([]byte(`{"amount":1200,"currency":"eur"}`), &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.Equal(NewMoney(1200, "EUR")) {
+		t.Fatalf("expected 1200 EUR, but got %+v", decoded)
+	}
+
+	err = json.Unmarshal([]byte(`{"amount":1200,"currency":"XYZ"}`), &decoded)
+	if err == nil {
+		t.Fatalf("expected an error for an unknown currency")
+	}
+}
@@ -0,0 +1,259 @@
+// Package dates reads the days and months given to the CLI, whether
+// written as ISO dates, in a local format, or relative to the present.
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DayLayout is the layout of days as sent to the server.
+	DayLayout = "2006-01-02"
+	// MonthLayout is the layout of months as written by users.
+	MonthLayout = "2006-01"
+)
+
+// Parser reads days and months relative to a given present.
+type Parser struct {
+	now time.Time
+	// layout is the time layout of the local format, if one is set
+	layout string
+	format string
+}
+
+// NewParser returns a Parser reading dates relative to the given time.
+// Besides ISO dates, it accepts dates written in the given local format,
+// which may be empty, or else spell the order of the year, month and day
+// as in DD/MM/YYYY.
+func NewParser(now time.Time, localFormat string) (*Parser, error) {
+	p := &Parser{now: now, format: localFormat}
+	if localFormat != "" {
+		layout, err := LayoutFromFormat(localFormat)
+		if err != nil {
+			return nil, err
+		}
+		p.layout = layout
+	}
+	return p, nil
+}
+
+// formatTokens replace the parts of a local date format with
+// those of a time layout. Longer tokens are listed first, so
+// that YYYY is not read as YY twice. Months and days map to
+// layout parts accepting one digit or two alike.
+var formatTokens = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MM", "1",
+	"DD", "2",
+	"M", "1",
+	"D", "2",
+)
+
+// LayoutFromFormat returns the time layout of a local date format, such as
+// DD/MM/YYYY or M.D.YY, in any case. The format must give the year, month
+// and day once each, separated by anything other than letters or digits.
+func LayoutFromFormat(format string) (string, error) {
+	invalid := fmt.Errorf("invalid date format '%s'; spell it with YYYY, MM and DD, as in DD/MM/YYYY", format)
+	if strings.ContainsFunc(format, func(r rune) bool { return r >= '0' && r <= '9' }) {
+		return "", invalid
+	}
+	layout := formatTokens.Replace(strings.ToUpper(format))
+	if strings.ContainsFunc(layout, func(r rune) bool { return r >= 'A' && r <= 'Z' }) {
+		return "", invalid
+	}
+	// a layout missing a part, or giving one twice,
+	// cannot read back a date it has written
+	sample := time.Date(2031, time.November, 28, 0, 0, 0, 0, time.UTC)
+	read, err := time.Parse(layout, sample.Format(layout))
+	if err != nil || !read.Equal(sample) {
+		return "", invalid
+	}
+	return layout, nil
+}
+
+// Today returns the start of the present day.
+func (p *Parser) Today() time.Time {
+	return startOfDay(p.now)
+}
+
+var (
+	offsetPattern  = regexp.MustCompile(`^([+-])(\d+)\s*([dwmy])$`)
+	weekdayPattern = regexp.MustCompile(`^(?:(last|next)\s+)?([a-z]+)$`)
+	monthPattern   = regexp.MustCompile(`^([a-z]+)(?:\s+(\d{4}))?$`)
+)
+
+// Day returns the start of the day described by the given input,
+// which may be one of:
+//
+//   - an ISO date, as in 2026-10-19, or a date in the local format
+//   - today, yesterday or tomorrow
+//   - an offset in days, weeks, months or years, as in -3d or +2w
+//   - a day of the week, as in friday or fri, meaning the latest one
+//     up to today; last friday, meaning the latest one before today;
+//     or next friday, meaning the first one after today
+func (p *Parser) Day(input string) (time.Time, error) {
+	s := normalize(input)
+	today := p.Today()
+
+	switch s {
+	case "today", "now":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if match := offsetPattern.FindStringSubmatch(s); match != nil {
+		n, err := strconv.Atoi(match[2])
+		if err != nil || n > 100000 {
+			return time.Time{}, fmt.Errorf("offset '%s' is too large", input)
+		}
+		if match[1] == "-" {
+			n = -n
+		}
+		switch match[3] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		case "m":
+			return addMonths(today, n), nil
+		default:
+			return addMonths(today, 12*n), nil
+		}
+	}
+
+	if match := weekdayPattern.FindStringSubmatch(s); match != nil {
+		if weekday, ok := lookupName(weekdayNames, match[2]); ok {
+			return relativeWeekday(today, time.Weekday(weekday), match[1]), nil
+		}
+	}
+
+	if day, err := time.ParseInLocation(DayLayout, s, p.now.Location()); err == nil {
+		return day, nil
+	}
+	if p.layout != "" {
+		if day, err := time.ParseInLocation(p.layout, s, p.now.Location()); err == nil {
+			return day, nil
+		}
+	}
+
+	examples := "YYYY-MM-DD"
+	if p.format != "" {
+		examples += ", " + p.format
+	}
+	return time.Time{}, fmt.Errorf("could not read date '%s'; try %s, today, yesterday, -3d or last friday", input, examples)
+}
+
+// Month returns the first day of the month described by the given input,
+// which may be one of:
+//
+//   - a month in the form YYYY-MM, as in 2026-10
+//   - this month, last month or next month
+//   - the name of a month, as in oct or october, meaning the latest one
+//     up to the present month, optionally followed by a year, as in oct 2025
+//   - anything accepted by Day, standing for the month of that day
+func (p *Parser) Month(input string) (time.Time, error) {
+	s := normalize(input)
+	thisMonth := startOfMonth(p.now)
+
+	switch s {
+	case "this month":
+		return thisMonth, nil
+	case "last month", "previous month":
+		return thisMonth.AddDate(0, -1, 0), nil
+	case "next month":
+		return thisMonth.AddDate(0, 1, 0), nil
+	}
+
+	if month, err := time.ParseInLocation(MonthLayout, s, p.now.Location()); err == nil {
+		return month, nil
+	}
+
+	if match := monthPattern.FindStringSubmatch(s); match != nil {
+		if number, ok := lookupName(monthNames, match[1]); ok {
+			month := time.Month(number + 1)
+			if match[2] != "" {
+				year, _ := strconv.Atoi(match[2])
+				return time.Date(year, month, 1, 0, 0, 0, 0, p.now.Location()), nil
+			}
+			named := time.Date(thisMonth.Year(), month, 1, 0, 0, 0, 0, p.now.Location())
+			if named.After(thisMonth) {
+				named = named.AddDate(-1, 0, 0)
+			}
+			return named, nil
+		}
+	}
+
+	day, err := p.Day(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not read month '%s'; try YYYY-MM, oct, this month or last month", input)
+	}
+	return startOfMonth(day), nil
+}
+
+var weekdayNames = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+var monthNames = []string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+// lookupName returns the index of the name which the given word
+// spells out in full, or abbreviates to at least three letters.
+func lookupName(names []string, word string) (int, bool) {
+	if len(word) < 3 {
+		return 0, false
+	}
+	for i, name := range names {
+		if strings.HasPrefix(name, word) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// relativeWeekday returns the day of the week nearest to today
+// in the direction given by the qualifier preceding its name.
+func relativeWeekday(today time.Time, weekday time.Weekday, qualifier string) time.Time {
+	back := (int(today.Weekday()) - int(weekday) + 7) % 7
+	switch qualifier {
+	case "last":
+		if back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back)
+	case "next":
+		return today.AddDate(0, 0, 7-back)
+	default:
+		return today.AddDate(0, 0, -back)
+	}
+}
+
+// addMonths moves a day by the given number of months, keeping to
+// the last day of the month where the same day does not exist, such
+// that a month before March 31 is the last day of February.
+func addMonths(day time.Time, months int) time.Time {
+	first := startOfMonth(day).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day.Day(), lastDay)-1)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// normalize lowers the case of input and collapses its whitespace.
+func normalize(input string) string {
+	return strings.Join(strings.Fields(strings.ToLower(input)), " ")
+}
@@ -0,0 +1,162 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// now is a Monday.
+var now = time.Date(2026, time.October, 19, 15, 30, 0, 0, time.UTC)
+
+func Test_Day(t *testing.T) {
+	tests := []struct {
+		input    string
+		format   string
+		expected string
+		wantErr  bool
+	}{
+		{input: "today", expected: "2026-10-19"},
+		{input: " Today ", expected: "2026-10-19"},
+		{input: "yesterday", expected: "2026-10-18"},
+		{input: "tomorrow", expected: "2026-10-20"},
+		{input: "-3d", expected: "2026-10-16"},
+		{input: "+2d", expected: "2026-10-21"},
+		{input: "-2w", expected: "2026-10-05"},
+		{input: "-1m", expected: "2026-09-19"},
+		{input: "-1y", expected: "2025-10-19"},
+		{input: "friday", expected: "2026-10-16"},
+		{input: "fri", expected: "2026-10-16"},
+		{input: "monday", expected: "2026-10-19"},
+		{input: "last friday", expected: "2026-10-16"},
+		{input: "last monday", expected: "2026-10-12"},
+		{input: "Last  Sunday", expected: "2026-10-18"},
+		{input: "next friday", expected: "2026-10-23"},
+		{input: "next monday", expected: "2026-10-26"},
+		{input: "2026-02-28", expected: "2026-02-28"},
+		{input: "19/10/2026", format: "DD/MM/YYYY", expected: "2026-10-19"},
+		{input: "5/1/2026", format: "DD/MM/YYYY", expected: "2026-01-05"},
+		{input: "2026-01-05", format: "DD/MM/YYYY", expected: "2026-01-05"},
+		{input: "10.19.26", format: "mm.dd.yy", expected: "2026-10-19"},
+		{input: "19/10/2026", wantErr: true},
+		{input: "13/13/2026", format: "DD/MM/YYYY", wantErr: true},
+		{input: "2026-02-30", wantErr: true},
+		{input: "fr", wantErr: true},
+		{input: "last", wantErr: true},
+		{input: "-3x", wantErr: true},
+		{input: "-9999999999d", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := NewParser(now, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			day, err := p.Day(tt.input)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if !tt.wantErr && day.Format(DayLayout) != tt.expected {
+				t.Fatalf("expected %s, but got %s", tt.expected, day.Format(DayLayout))
+			}
+		})
+	}
+}
+
+func Test_DayOffsetAcrossMonthEnds(t *testing.T) {
+	tests := []struct {
+		now      time.Time
+		input    string
+		expected string
+	}{
+		{now: time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC), input: "-1m", expected: "2026-02-28"},
+		{now: time.Date(2028, time.March, 31, 0, 0, 0, 0, time.UTC), input: "-1m", expected: "2028-02-29"},
+		{now: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC), input: "+1y", expected: "2029-02-28"},
+		{now: time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), input: "+3m", expected: "2026-04-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.now.Format(DayLayout)+" "+tt.input, func(t *testing.T) {
+			p, _ := NewParser(tt.now, "")
+			day, err := p.Day(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if day.Format(DayLayout) != tt.expected {
+				t.Fatalf("expected %s, but got %s", tt.expected, day.Format(DayLayout))
+			}
+		})
+	}
+}
+
+func Test_Month(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "2026-10", expected: "2026-10"},
+		{input: "2025-01", expected: "2025-01"},
+		{input: "this month", expected: "2026-10"},
+		{input: "last month", expected: "2026-09"},
+		{input: "next month", expected: "2026-11"},
+		{input: "oct", expected: "2026-10"},
+		{input: "October", expected: "2026-10"},
+		{input: "sep", expected: "2026-09"},
+		{input: "nov", expected: "2025-11"},
+		{input: "dec 2027", expected: "2027-12"},
+		{input: "may", expected: "2026-05"},
+		{input: "today", expected: "2026-10"},
+		{input: "-2m", expected: "2026-08"},
+		{input: "2026-03-15", expected: "2026-03"},
+		{input: "2026-13", wantErr: true},
+		{input: "octember", wantErr: true},
+		{input: "oc", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, _ := NewParser(now, "")
+			month, err := p.Month(tt.input)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if month.Format(MonthLayout) != tt.expected || month.Day() != 1 {
+				t.Fatalf("expected the first of %s, but got %s", tt.expected, month.Format(DayLayout))
+			}
+		})
+	}
+}
+
+func Test_LayoutFromFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: "DD/MM/YYYY"},
+		{format: "MM/DD/YYYY"},
+		{format: "dd.mm.yyyy"},
+		{format: "YYYY/MM/DD"},
+		{format: "D-M-YY"},
+		{format: "YYYYMMDD"},
+		{format: "DD/MM", wantErr: true},
+		{format: "DD/DD/YYYY", wantErr: true},
+		{format: "DD/MM/YYYY hh:mm", wantErr: true},
+		{format: "DD/MM/2006", wantErr: true},
+		{format: "Mon DD YYYY", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			_, err := LayoutFromFormat(tt.format)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Package exchange keeps a local table of dated exchange rates
+// between currencies, used to convert amounts for display.
+package exchange
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	file "github.com/YouWantToPinch/pincher-cli/internal/filemgr"
+)
+
+// DateLayout is the layout in which the dates of rates are written.
+const DateLayout = "2006-01-02"
+
+// Rate is the value of one unit of a currency in
+// units of another, as of a given date.
+type Rate struct {
+	Date string `json:"date"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Rate is kept as written, such that it stays exact.
+	Rate string `json:"rate"`
+}
+
+// NewRate validates the given currency codes and rate,
+// returning a Rate if they are fit for conversion.
+func NewRate(from, to, rate string, date time.Time) (Rate, error) {
+	fromCurrency, err := cc.Lookup(from)
+	if err != nil {
+		return Rate{}, err
+	}
+	toCurrency, err := cc.Lookup(to)
+	if err != nil {
+		return Rate{}, err
+	}
+	if fromCurrency.ISOCode == toCurrency.ISOCode {
+		return Rate{}, fmt.Errorf("cannot set a rate from %s to itself", fromCurrency.ISOCode)
+	}
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("rate '%s' must be a positive number", rate)
+	}
+	return Rate{
+		Date: date.Format(DateLayout),
+		From: fromCurrency.ISOCode,
+		To:   toCurrency.ISOCode,
+		Rate: strings.TrimSpace(rate),
+	}, nil
+}
+
+// Value returns the rate as an exact number.
+func (r Rate) Value() *big.Rat {
+	value, ok := new(big.Rat).SetString(r.Rate)
+	if !ok {
+		return new(big.Rat)
+	}
+	return value
+}
+
+// Table is a collection of rates, as kept in a local file.
+type Table struct {
+	Rates []Rate `json:"rates"`
+}
+
+// Set adds a rate to the table, replacing any rate between the
+// same currencies on the same date. It reports whether a rate
+// was replaced.
+func (t *Table) Set(rate Rate) bool {
+	for i, r := range t.Rates {
+		if r.Date == rate.Date && r.From == rate.From && r.To == rate.To {
+			t.Rates[i] = rate
+			return true
+		}
+	}
+	t.Rates = append(t.Rates, rate)
+	return false
+}
+
+// Remove deletes the rate between the given currencies on
+// the given date, reporting whether there was one to delete.
+func (t *Table) Remove(from, to string, date time.Time) bool {
+	day := date.Format(DateLayout)
+	for i, r := range t.Rates {
+		if r.Date == day && strings.EqualFold(r.From, from) && strings.EqualFold(r.To, to) {
+			t.Rates = append(t.Rates[:i], t.Rates[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the most recent rate from one currency to another as
+// of the given date, along with its value. A rate recorded the other
+// way around is used, inverted, if no direct rate is as recent.
+func (t *Table) Find(from, to string, on time.Time) (Rate, *big.Rat, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return Rate{Date: on.Format(DateLayout), From: from, To: to, Rate: "1"}, big.NewRat(1, 1), true
+	}
+	day := on.Format(DateLayout)
+	var best Rate
+	var inverted, found bool
+	for _, r := range t.Rates {
+		direct := r.From == from && r.To == to
+		inverse := r.From == to && r.To == from
+		if r.Date > day || (!direct && !inverse) {
+			continue
+		}
+		// a direct rate wins over an inverse one of the same date
+		if found && (r.Date < best.Date || (r.Date == best.Date && !(direct && inverted))) {
+			continue
+		}
+		best, inverted, found = r, inverse, true
+	}
+	if !found {
+		return Rate{}, nil, false
+	}
+	value := best.Value()
+	if inverted {
+		value.Inv(value)
+	}
+	return best, value, true
+}
+
+// Filter returns the rates involving the given currencies, in either
+// direction, sorted by date. An empty code matches any currency.
+func (t *Table) Filter(from, to string) []Rate {
+	matches := func(code, query string) bool {
+		return query == "" || strings.EqualFold(code, query)
+	}
+	rates := []Rate{}
+	for _, r := range t.Rates {
+		if (matches(r.From, from) && matches(r.To, to)) || (matches(r.From, to) && matches(r.To, from)) {
+			rates = append(rates, r)
+		}
+	}
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].Date != rates[j].Date {
+			return rates[i].Date < rates[j].Date
+		}
+		return rates[i].From+rates[i].To < rates[j].From+rates[j].To
+	})
+	return rates
+}
+
+// ImportCSV adds rates read from CSV records of the form
+// date,from,to,rate to the table, returning how many were read.
+// A header row is skipped, if present. No rates are added
+// unless all of them could be read.
+func (t *Table) ImportCSV(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	rates := []Rate{}
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+		date, err := time.Parse(DateLayout, record[0])
+		if err != nil {
+			return 0, fmt.Errorf("line %d: bad date '%s'; use YYYY-MM-DD", i+1, record[0])
+		}
+		rate, err := NewRate(record[1], record[2], record[3], date)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+	for _, rate := range rates {
+		t.Set(rate)
+	}
+	return len(rates), nil
+}
+
+// ReadFromFile loads the table of rates from the local machine,
+// returning an empty table if none has been saved yet.
+func ReadFromFile() (*Table, error) {
+	path, err := file.GetConfigFilepath("rates.json")
+	if err != nil {
+		return nil, err
+	}
+	table, err := file.ReadJSONFromFile[Table](path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Table{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (t *Table) WriteToFile() error {
+	path, err := file.GetConfigFilepath("rates.json")
+	if err != nil {
+		return err
+	}
+	return file.WriteAsJSON(t, path)
+}
@@ -0,0 +1,157 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustRate(t *testing.T, from, to, rate, date string) Rate {
+	t.Helper()
+	day, err := time.Parse(DateLayout, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRate(from, to, rate, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNewRate(t *testing.T) {
+	tests := []struct {
+		from, to, rate string
+		wantErr        bool
+	}{
+		{from: "EUR", to: "USD", rate: "1.0845"},
+		{from: "eur", to: "usd", rate: "1.0845"},
+		{from: "EUR", to: "USD", rate: "0", wantErr: true},
+		{from: "EUR", to: "USD", rate: "-1.2", wantErr: true},
+		{from: "EUR", to: "USD", rate: "abc", wantErr: true},
+		{from: "EUR", to: "EUR", rate: "1", wantErr: true},
+		{from: "ABC", to: "USD", rate: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s->%s@%s", tt.from, tt.to, tt.rate), func(t *testing.T) {
+			rate, err := NewRate(tt.from, tt.to, tt.rate, time.Now())
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if err == nil && (rate.From != "EUR" || rate.To != "USD") {
+				t.Fatalf("expected codes to be normalized, but got %s->%s", rate.From, rate.To)
+			}
+		})
+	}
+}
+
+func TestTableFind(t *testing.T) {
+	table := &Table{}
+	table.Set(mustRate(t, "EUR", "USD", "1.10", "2026-01-01"))
+	table.Set(mustRate(t, "EUR", "USD", "1.20", "2026-06-01"))
+	table.Set(mustRate(t, "USD", "EUR", "0.80", "2026-09-01"))
+	table.Set(mustRate(t, "GBP", "USD", "1.25", "2026-03-01"))
+	table.Set(mustRate(t, "USD", "GBP", "0.50", "2026-03-01"))
+
+	tests := []struct {
+		from, to, on string
+		wantDate     string
+		wantValue    string
+		wantFound    bool
+	}{
+		{from: "EUR", to: "USD", on: "2025-12-31", wantFound: false},
+		{from: "EUR", to: "USD", on: "2026-01-01", wantDate: "2026-01-01", wantValue: "11/10", wantFound: true},
+		{from: "EUR", to: "USD", on: "2026-07-15", wantDate: "2026-06-01", wantValue: "6/5", wantFound: true},
+		{from: "USD", to: "EUR", on: "2026-07-15", wantDate: "2026-06-01", wantValue: "5/6", wantFound: true},
+		{from: "EUR", to: "USD", on: "2026-10-19", wantDate: "2026-09-01", wantValue: "5/4", wantFound: true},
+		{from: "gbp", to: "usd", on: "2026-10-19", wantDate: "2026-03-01", wantValue: "5/4", wantFound: true},
+		{from: "USD", to: "USD", on: "2026-10-19", wantDate: "2026-10-19", wantValue: "1", wantFound: true},
+		{from: "JPY", to: "USD", on: "2026-10-19", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s->%s on %s", tt.from, tt.to, tt.on), func(t *testing.T) {
+			on, _ := time.Parse(DateLayout, tt.on)
+			rate, value, found := table.Find(tt.from, tt.to, on)
+			if found != tt.wantFound {
+				t.Fatalf("expected found: %v, but got: %v", tt.wantFound, found)
+			}
+			if !found {
+				return
+			}
+			if rate.Date != tt.wantDate || value.RatString() != tt.wantValue {
+				t.Fatalf("expected rate %s on %s, but got %s on %s", tt.wantValue, tt.wantDate, value.RatString(), rate.Date)
+			}
+		})
+	}
+}
+
+func TestTableSetAndRemove(t *testing.T) {
+	table := &Table{}
+	if replaced := table.Set(mustRate(t, "EUR", "USD", "1.10", "2026-01-01")); replaced {
+		t.Fatalf("expected new rate not to replace another")
+	}
+	if replaced := table.Set(mustRate(t, "EUR", "USD", "1.15", "2026-01-01")); !replaced {
+		t.Fatalf("expected rate of the same date to be replaced")
+	}
+	if len(table.Rates) != 1 || table.Rates[0].Rate != "1.15" {
+		t.Fatalf("expected a single rate of 1.15, but got %v", table.Rates)
+	}
+	day, _ := time.Parse(DateLayout, "2026-01-01")
+	if !table.Remove("eur", "usd", day) || len(table.Rates) != 0 {
+		t.Fatalf("expected rate to be removed, but got %v", table.Rates)
+	}
+	if table.Remove("EUR", "USD", day) {
+		t.Fatalf("expected nothing left to remove")
+	}
+}
+
+func TestTableImportCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:      "with header",
+			input:     "date,from,to,rate\n2026-01-01,EUR,USD,1.10\n2026-02-01,EUR,USD,1.12\n",
+			wantCount: 2,
+		},
+		{
+			name:      "without header",
+			input:     "2026-01-01, GBP, USD, 1.25\n",
+			wantCount: 1,
+		},
+		{
+			name:    "bad date",
+			input:   "2026-13-01,EUR,USD,1.10\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad rate on a later line",
+			input:   "2026-01-01,EUR,USD,1.10\n2026-02-01,EUR,USD,nope\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong number of fields",
+			input:   "2026-01-01,EUR,USD\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &Table{}
+			count, err := table.ImportCSV(strings.NewReader(tt.input))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if count != tt.wantCount || len(table.Rates) != tt.wantCount {
+				t.Fatalf("expected %d rates, but read %d and kept %d", tt.wantCount, count, len(table.Rates))
+			}
+		})
+	}
+}
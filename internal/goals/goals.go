@@ -0,0 +1,239 @@
+// Package goals keeps a local book of the goals set for budget
+// categories, and works out the progress made toward each.
+package goals
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+	"github.com/YouWantToPinch/pincher-cli/internal/dates"
+	file "github.com/YouWantToPinch/pincher-cli/internal/filemgr"
+)
+
+// The kinds of goal which may be set for a category.
+const (
+	// KindMonthly is an amount to assign to a category every month.
+	KindMonthly = "monthly"
+	// KindTarget is a balance for a category to reach by a month.
+	KindTarget = "target"
+	// KindCap is the most to spend from a category in a month.
+	KindCap = "cap"
+)
+
+// Kinds are the kinds of goal which may be set.
+var Kinds = []string{KindMonthly, KindTarget, KindCap}
+
+// Goal is a goal set for a single category.
+type Goal struct {
+	BudgetID   string   `json:"budget_id"`
+	CategoryID string   `json:"category_id"`
+	Kind       string   `json:"kind"`
+	Amount     cc.Money `json:"amount"`
+	// By is the month by which a target balance is to be
+	// reached, written as YYYY-MM; it is unused otherwise.
+	By string `json:"by,omitempty"`
+}
+
+// NewGoal validates the given kind of goal, returning a Goal if it is
+// fit to be set. Targets must be given the month they are due by,
+// and no other kind of goal may be.
+func NewGoal(budgetID, categoryID, kind string, amount cc.Money, by time.Time) (Goal, error) {
+	goal := Goal{BudgetID: budgetID, CategoryID: categoryID, Kind: kind, Amount: amount}
+	switch kind {
+	case KindMonthly, KindCap:
+		if !by.IsZero() {
+			return Goal{}, fmt.Errorf("only a target may be given a month it is due by, not a %s goal", kind)
+		}
+	case KindTarget:
+		if by.IsZero() {
+			return Goal{}, fmt.Errorf("a target must be given the month it is due by")
+		}
+		goal.By = by.Format(dates.MonthLayout)
+	default:
+		return Goal{}, fmt.Errorf("unknown kind of goal '%s'; use monthly, target or cap", kind)
+	}
+	if !amount.IsPositive() {
+		return Goal{}, fmt.Errorf("the amount of a goal must be more than zero")
+	}
+	return goal, nil
+}
+
+// String describes the goal, as in "$200.00 a month".
+func (g Goal) String() string {
+	switch g.Kind {
+	case KindTarget:
+		return fmt.Sprintf("%s by %s", g.Amount, g.By)
+	case KindCap:
+		return fmt.Sprintf("at most %s a month", g.Amount)
+	default:
+		return fmt.Sprintf("%s a month", g.Amount)
+	}
+}
+
+// Progress is how far a category has come toward its goal in a month.
+type Progress struct {
+	Goal Goal
+	// Toward is the amount counted toward the goal: the money assigned
+	// in the month to a monthly goal, the balance of a target, or the
+	// money spent under a cap.
+	Toward cc.Money
+	// Needed is the money still to be assigned in the month to keep
+	// to the goal. It is always zero under a cap.
+	Needed cc.Money
+	// Fraction is the part of the goal met, being more than one
+	// once a cap has been exceeded.
+	Fraction float64
+}
+
+// Underfunded reports whether the goal needs more money in the month.
+func (p Progress) Underfunded() bool {
+	return p.Needed.IsPositive()
+}
+
+// Over reports whether more was spent than a cap allows.
+func (p Progress) Over() bool {
+	return p.Goal.Kind == KindCap && p.Fraction > 1
+}
+
+// Progress works out the progress made toward the goal in a month, given
+// the money assigned to the category in the month, its activity, and
+// its balance at the end of the month. A target spreads what is left
+// to reach it evenly over the months remaining until it is due.
+func (g Goal) Progress(month time.Time, assigned, activity, balance cc.Money) (Progress, error) {
+	zero := cc.NewMoney(0, g.Amount.ISOCode)
+	p := Progress{Goal: g, Needed: zero}
+	var err error
+	switch g.Kind {
+	case KindMonthly:
+		p.Toward = assigned
+		if p.Needed, err = g.Amount.Sub(assigned); err != nil {
+			return Progress{}, err
+		}
+	case KindTarget:
+		p.Toward = balance
+		due, err := time.Parse(dates.MonthLayout, g.By)
+		if err != nil {
+			return Progress{}, fmt.Errorf("bad month of target '%s': %w", g.By, err)
+		}
+		monthsLeft := int64(1)
+		if left := monthsBetween(month, due) + 1; left > 1 {
+			monthsLeft = left
+		}
+		// the balance before anything was assigned this month
+		// is what the money needed this month builds upon
+		before, err := balance.Sub(assigned)
+		if err != nil {
+			return Progress{}, err
+		}
+		remaining, err := g.Amount.Sub(before)
+		if err != nil {
+			return Progress{}, err
+		}
+		if remaining.IsPositive() {
+			// rounded up, such that the target is met on time
+			share := (remaining.Amount + monthsLeft - 1) / monthsLeft
+			if p.Needed, err = cc.NewMoney(share, remaining.ISOCode).Sub(assigned); err != nil {
+				return Progress{}, err
+			}
+		}
+	case KindCap:
+		p.Toward = zero
+		if activity.IsNegative() {
+			if p.Toward, err = activity.Neg(); err != nil {
+				return Progress{}, err
+			}
+		}
+	default:
+		return Progress{}, fmt.Errorf("unknown kind of goal '%s'", g.Kind)
+	}
+
+	if p.Needed.IsNegative() {
+		p.Needed = zero
+	}
+	if _, err := p.Toward.Cmp(g.Amount); err != nil {
+		return Progress{}, err
+	}
+	p.Fraction = float64(max(p.Toward.Amount, 0)) / float64(g.Amount.Amount)
+	return p, nil
+}
+
+// monthsBetween returns the number of months from one month to another,
+// being negative if the second comes first.
+func monthsBetween(from, to time.Time) int64 {
+	return int64(to.Year()-from.Year())*12 + int64(to.Month()-from.Month())
+}
+
+// Book holds the goals set for categories, by category ID.
+type Book struct {
+	Goals map[string]Goal `json:"goals"`
+}
+
+// Set adds a goal to the book, replacing any goal already set for
+// the same category. It reports whether a goal was replaced.
+func (b *Book) Set(goal Goal) bool {
+	if b.Goals == nil {
+		b.Goals = map[string]Goal{}
+	}
+	_, replaced := b.Goals[goal.CategoryID]
+	b.Goals[goal.CategoryID] = goal
+	return replaced
+}
+
+// Get returns the goal set for a category, if there is one.
+func (b *Book) Get(categoryID string) (Goal, bool) {
+	goal, ok := b.Goals[categoryID]
+	return goal, ok
+}
+
+// Remove deletes the goal set for a category,
+// reporting whether there was one to delete.
+func (b *Book) Remove(categoryID string) bool {
+	if _, ok := b.Goals[categoryID]; !ok {
+		return false
+	}
+	delete(b.Goals, categoryID)
+	return true
+}
+
+// ForBudget returns the goals set for the categories
+// of a budget, in order of category ID.
+func (b *Book) ForBudget(budgetID string) []Goal {
+	goals := []Goal{}
+	for _, goal := range b.Goals {
+		if goal.BudgetID == budgetID {
+			goals = append(goals, goal)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].CategoryID < goals[j].CategoryID })
+	return goals
+}
+
+// ReadFromFile loads the book of goals from the local machine,
+// returning an empty book if none has been saved yet.
+func ReadFromFile() (*Book, error) {
+	path, err := file.GetConfigFilepath("goals.json")
+	if err != nil {
+		return nil, err
+	}
+	book, err := file.ReadJSONFromFile[Book](path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Book{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// WriteToFile saves the book of goals to the local machine.
+func (b *Book) WriteToFile() error {
+	path, err := file.GetConfigFilepath("goals.json")
+	if err != nil {
+		return err
+	}
+	return file.WriteAsJSON(b, path)
+}
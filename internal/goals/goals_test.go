@@ -0,0 +1,183 @@
+package goals
+
+import (
+	"testing"
+	"time"
+
+	cc "github.com/YouWantToPinch/pincher-cli/internal/currency"
+)
+
+func usd(amount int64) cc.Money { return cc.NewMoney(amount, "USD") }
+
+func month(year int, m time.Month) time.Time { return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC) }
+
+func TestNewGoal(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		amount  cc.Money
+		by      time.Time
+		wantErr bool
+	}{
+		{name: "monthly", kind: KindMonthly, amount: usd(20000)},
+		{name: "target", kind: KindTarget, amount: usd(100000), by: month(2027, time.June)},
+		{name: "target without a month", kind: KindTarget, amount: usd(100000), wantErr: true},
+		{name: "cap", kind: KindCap, amount: usd(30000)},
+		{name: "monthly with a month", kind: KindMonthly, amount: usd(20000), by: month(2027, time.June), wantErr: true},
+		{name: "cap with a month", kind: KindCap, amount: usd(30000), by: month(2027, time.June), wantErr: true},
+		{name: "unknown kind", kind: "weekly", amount: usd(30000), wantErr: true},
+		{name: "nothing to aim for", kind: KindMonthly, amount: usd(0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal, err := NewGoal("budget", "category", tt.kind, tt.amount, tt.by)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, but got: %v, with err value: %v", tt.wantErr, (err != nil), err)
+			}
+			if err == nil && tt.kind == KindTarget && goal.By != "2027-06" {
+				t.Errorf("expected target due by 2027-06, got %s", goal.By)
+			}
+		})
+	}
+}
+
+func TestGoalProgress(t *testing.T) {
+	october := month(2026, time.October)
+	tests := []struct {
+		name             string
+		goal             Goal
+		assigned         cc.Money
+		activity         cc.Money
+		balance          cc.Money
+		expectedToward   cc.Money
+		expectedNeeded   cc.Money
+		expectedFraction float64
+		underfunded      bool
+		over             bool
+	}{
+		{
+			name:             "monthly partly funded",
+			goal:             Goal{Kind: KindMonthly, Amount: usd(20000)},
+			assigned:         usd(5000),
+			activity:         usd(-1000),
+			balance:          usd(4000),
+			expectedToward:   usd(5000),
+			expectedNeeded:   usd(15000),
+			expectedFraction: 0.25,
+			underfunded:      true,
+		},
+		{
+			name:             "monthly more than funded",
+			goal:             Goal{Kind: KindMonthly, Amount: usd(20000)},
+			assigned:         usd(25000),
+			balance:          usd(25000),
+			expectedToward:   usd(25000),
+			expectedNeeded:   usd(0),
+			expectedFraction: 1.25,
+		},
+		{
+			name:             "target spread over the months left",
+			goal:             Goal{Kind: KindTarget, Amount: usd(100000), By: "2027-01"},
+			assigned:         usd(0),
+			balance:          usd(20000),
+			expectedToward:   usd(20000),
+			expectedNeeded:   usd(20000),
+			expectedFraction: 0.2,
+			underfunded:      true,
+		},
+		{
+			name:             "target share rounded up and partly assigned",
+			goal:             Goal{Kind: KindTarget, Amount: usd(10000), By: "2026-12"},
+			assigned:         usd(1000),
+			balance:          usd(1000),
+			expectedToward:   usd(1000),
+			expectedNeeded:   usd(2334),
+			expectedFraction: 0.1,
+			underfunded:      true,
+		},
+		{
+			name:             "target overdue needs the rest at once",
+			goal:             Goal{Kind: KindTarget, Amount: usd(10000), By: "2026-06"},
+			assigned:         usd(0),
+			balance:          usd(4000),
+			expectedToward:   usd(4000),
+			expectedNeeded:   usd(6000),
+			expectedFraction: 0.4,
+			underfunded:      true,
+		},
+		{
+			name:             "cap exceeded",
+			goal:             Goal{Kind: KindCap, Amount: usd(20000)},
+			assigned:         usd(20000),
+			activity:         usd(-30000),
+			balance:          usd(-10000),
+			expectedToward:   usd(30000),
+			expectedNeeded:   usd(0),
+			expectedFraction: 1.5,
+			over:             true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.activity.ISOCode == "" {
+				tt.activity = usd(0)
+			}
+			p, err := tt.goal.Progress(october, tt.assigned, tt.activity, tt.balance)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !p.Toward.Equal(tt.expectedToward) {
+				t.Errorf("expected %v toward the goal, got %v", tt.expectedToward, p.Toward)
+			}
+			if !p.Needed.Equal(tt.expectedNeeded) {
+				t.Errorf("expected %v needed, got %v", tt.expectedNeeded, p.Needed)
+			}
+			if p.Fraction != tt.expectedFraction {
+				t.Errorf("expected fraction %v, got %v", tt.expectedFraction, p.Fraction)
+			}
+			if p.Underfunded() != tt.underfunded {
+				t.Errorf("expected underfunded: %v", tt.underfunded)
+			}
+			if p.Over() != tt.over {
+				t.Errorf("expected over: %v", tt.over)
+			}
+		})
+	}
+}
+
+func TestGoalProgressCurrencyMismatch(t *testing.T) {
+	goal := Goal{Kind: KindMonthly, Amount: usd(20000)}
+	eur := cc.NewMoney(5000, "EUR")
+	if _, err := goal.Progress(month(2026, time.October), eur, eur, eur); err == nil {
+		t.Fatal("expected an error for figures in another currency than the goal")
+	}
+}
+
+func TestBook(t *testing.T) {
+	book := &Book{}
+	monthly := Goal{BudgetID: "b1", CategoryID: "c2", Kind: KindMonthly, Amount: usd(100)}
+	if book.Set(monthly) {
+		t.Error("expected no goal to be replaced")
+	}
+	book.Set(Goal{BudgetID: "b1", CategoryID: "c1", Kind: KindCap, Amount: usd(100)})
+	book.Set(Goal{BudgetID: "b2", CategoryID: "c3", Kind: KindCap, Amount: usd(100)})
+	if !book.Set(Goal{BudgetID: "b1", CategoryID: "c2", Kind: KindCap, Amount: usd(500)}) {
+		t.Error("expected the goal of c2 to be replaced")
+	}
+
+	goals := book.ForBudget("b1")
+	if len(goals) != 2 || goals[0].CategoryID != "c1" || goals[1].CategoryID != "c2" {
+		t.Fatalf("expected goals of c1 and c2, got %+v", goals)
+	}
+	if goal, _ := book.Get("c2"); goal.Kind != KindCap {
+		t.Errorf("expected the goal of c2 to be a cap, got %s", goal.Kind)
+	}
+	if !book.Remove("c2") || book.Remove("c2") {
+		t.Error("expected the goal of c2 to be removed once")
+	}
+	if _, ok := book.Get("c2"); ok {
+		t.Error("expected no goal for c2")
+	}
+}
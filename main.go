@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/YouWantToPinch/pincher-cli/internal/cli"
 	"github.com/YouWantToPinch/pincher-cli/internal/config"
@@ -63,12 +64,26 @@ func main() {
 		cliState.Config.RefreshToken = ""
 	}
 
+	// run a single command if one was given as arguments
+	if len(os.Args) > 1 {
+		exitCode := cli.RunOnce(cliState, os.Args[1:])
+		saveSession(cliState)
+		Quit(cliState.Logger)
+		os.Exit(exitCode)
+	}
+
 	// run the repl until it is closed from within
 	go func() {
 		cli.StartRepl(cliState)
 	}()
 
 	<-done
+	saveSession(cliState)
+}
+
+// saveSession persists the config, and the cache if the
+// user wants to stay logged in, before the program exits.
+func saveSession(cliState *cli.State) {
 	if cliState.Config.StayLoggedIn {
 		// update config to track refresh token for user
 		// to log in again automatically